@@ -0,0 +1,124 @@
+package manaxclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ctxReader wraps an io.Reader so that reads fail with ctx.Err() as soon
+// as the context is done. It makes plain io.Copy loops cancellable when
+// the source itself is not context-aware (files, pipes, slow devices).
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// newCtxReader returns r wrapped so that it honours ctx cancellation.
+func newCtxReader(ctx context.Context, r io.Reader) io.Reader {
+	return &ctxReader{ctx: ctx, r: r}
+}
+
+// Read implements io.Reader. The context is checked before every read,
+// so cancellation is observed at the latest after the in-flight Read of
+// the underlying reader returns.
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// audioAtLeast reports whether audio holds at least n bytes. Only readers
+// reporting their remaining length (bytes.Reader, strings.Reader,
+// bytes.Buffer, ...) can be measured; any other reader is assumed to be
+// large.
+func audioAtLeast(audio io.Reader, n int64) bool {
+	if l, ok := audio.(interface{ Len() int }); ok {
+		return int64(l.Len()) >= n
+	}
+	return true
+}
+
+// streamBodyThreshold is the text size above which request bodies are
+// streamed through a pipe instead of being marshalled in memory.
+const streamBodyThreshold = 1 << 20
+
+// newSpeechTextBody returns the JSON body of UploadSpeechText for in,
+// encoded on the fly into a pipe so that Text is never copied as a whole.
+// The returned reader must be closed; closing it early stops the encoder.
+func newSpeechTextBody(in UploadSpeechTextRequest) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		err := writeSpeechTextJSON(w, in)
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// writeSpeechTextJSON writes in as the JSON object documented on
+// UploadSpeechText. The output is the same as json.Marshal(in).
+func writeSpeechTextJSON(w *bufio.Writer, in UploadSpeechTextRequest) error {
+	head := struct {
+		ProID     string `json:"proId"`
+		SessionID string `json:"sessionId"`
+	}{in.ProID, in.SessionID}
+	data, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	w.Write(data[:len(data)-1])
+	w.WriteString(`,"chunkIndex":`)
+	w.WriteString(strconv.Itoa(in.ChunkIndex))
+	w.WriteString(`,"text":`)
+	if err := writeJSONString(w, in.Text, jsonStringChunk); err != nil {
+		return err
+	}
+	return w.WriteByte('}')
+}
+
+// jsonStringChunk is the size of the pieces writeJSONString encodes.
+const jsonStringChunk = 32 << 10
+
+// writeJSONString writes s as a quoted JSON string without building the
+// escaped copy in memory: s is encoded with a json.Encoder in pieces of
+// about chunk bytes, cut on rune boundaries, so the output is the same
+// as json.Marshal(s), including HTML escaping and the replacement of
+// invalid UTF-8.
+func writeJSONString(w io.Writer, s string, chunk int) error {
+	chunk = max(chunk, utf8.UTFMax)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if _, err := io.WriteString(w, `"`); err != nil {
+		return err
+	}
+	for len(s) > 0 {
+		n := min(chunk, len(s))
+		// Back up to the start of the rune being cut. A rune has at most
+		// utf8.UTFMax-1 continuation bytes; beyond that they are invalid
+		// anyway and encode the same on either side of the cut.
+		for back := 0; n < len(s) && back < utf8.UTFMax-1 && !utf8.RuneStart(s[n]); back++ {
+			n--
+		}
+		buf.Reset()
+		if err := enc.Encode(s[:n]); err != nil {
+			return err
+		}
+		// Strip the quotes and the newline added by Encode.
+		b := buf.Bytes()
+		if _, err := w.Write(b[1 : len(b)-2]); err != nil {
+			return err
+		}
+		s = s[n:]
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
@@ -2,6 +2,7 @@ package manaxclient
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -13,6 +14,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,8 +25,9 @@ import (
 //   - Underlying HTTP client (connection pooling, timeouts).
 //   - Optional "identity" headers X-Pro-Id / X-Pro-Token used by server middleware.
 //
-// All methods are safe for concurrent use provided that SetAuth is not
-// called concurrently with in-flight requests.
+// All methods are safe for concurrent use. SetAuth and ClearAuth may be
+// called while requests are in flight; each request uses the credentials
+// current when it was built.
 type Client struct {
 	// baseURL is the parsed base URL for the API, for example:
 	//   https://api.manax.pro
@@ -38,6 +41,10 @@ type Client struct {
 	// If nil, http.DefaultClient is used.
 	httpClient *http.Client
 
+	// authMu guards proID and proToken, which SetAuth and ClearAuth may
+	// change while requests are in flight.
+	authMu sync.RWMutex
+
 	// proID is the current logical identity ("profile id") that will
 	// be propagated via X-Pro-Id header if non-empty.
 	proID string
@@ -45,6 +52,81 @@ type Client struct {
 	// proToken is the current logical "secret" or token that will be
 	// propagated via X-Pro-Token header if non-empty.
 	proToken string
+
+	// connectivityTimeout, when positive, makes NewClientWithOptions
+	// verify that the base URL is reachable (see WithConnectivityCheck).
+	connectivityTimeout time.Duration
+
+	// notFoundAsError maps 404 responses of lookup methods to ErrNotFound
+	// (see WithNotFoundAsError).
+	notFoundAsError bool
+
+	// supportedSampleRates, when non-empty, restricts the sample rates
+	// accepted by UploadSpeechAudio (see WithSupportedSampleRates).
+	supportedSampleRates []int
+
+	// deleteNotFoundOK treats 404 responses of delete methods as success
+	// (see WithDeleteNotFoundOK).
+	deleteNotFoundOK bool
+
+	// logger and metrics, when set, receive diagnostic events such as
+	// retries (see WithLogger, WithMetrics).
+	logger  Logger
+	metrics Metrics
+
+	// statusCache, when set, caches terminal GetSpeechStatusByID results
+	// (see WithSpeechStatusCache).
+	statusCache *statusCache
+
+	// maxResponseBytes, when positive, caps the bytes read from
+	// non-streaming response bodies (see WithMaxResponseBytes).
+	maxResponseBytes int64
+
+	// defaultProIDFromAuth makes methods fall back to proID when called
+	// with an empty profile id (see WithDefaultProIDFromAuth).
+	defaultProIDFromAuth bool
+
+	// ownedTransport is the transport of the httpClient created by
+	// transport options such as WithMinTLSVersion; nil when httpClient
+	// was supplied by the caller or http.DefaultClient is used.
+	ownedTransport *http.Transport
+
+	// gzipAudio and gzipAudioMinBytes configure compressed audio uploads
+	// (see WithGzipAudioUploads).
+	gzipAudio         bool
+	gzipAudioMinBytes int64
+
+	// checkProfileMismatch enables checkProID (see
+	// WithProfileMismatchCheck).
+	checkProfileMismatch bool
+
+	// maxRequestDuration, when positive, bounds non-streaming requests
+	// whose context has no deadline (see WithMaxRequestDuration).
+	maxRequestDuration time.Duration
+
+	// rateLimitMu guards lastRateLimit, the state reported by the most
+	// recent response with rate-limit headers (see LastRateLimit).
+	rateLimitMu   sync.Mutex
+	lastRateLimit *RateLimitStatus
+
+	// uploadSem, when non-nil, bounds the number of concurrent audio
+	// uploads to its capacity (see WithMaxConcurrentUploads).
+	uploadSem chan struct{}
+
+	// signer, when set, is called on every request right before it is
+	// sent (see WithRequestSigner).
+	signer RequestSigner
+
+	// retry, when set, makes doRaw retry failed requests (see WithRetry).
+	retry *RetryConfig
+
+	// middlewares wrap the sending of every request, first one outermost
+	// (see WithMiddleware).
+	middlewares []Middleware
+
+	// lenientStreamContentType disables the Content-Type check of stream
+	// responses (see WithLenientStreamContentType).
+	lenientStreamContentType bool
 }
 
 // NewClient constructs a new Client for the given baseURL string.
@@ -72,8 +154,9 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	u.Fragment = ""
 
 	c := &Client{
-		baseURL:    u,
-		httpClient: httpClient,
+		baseURL:            u,
+		httpClient:         httpClient,
+		maxRequestDuration: DefaultMaxRequestDuration,
 	}
 	return c, nil
 }
@@ -84,18 +167,67 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 // proID is the logical profile identifier (for example: "p_123").
 // proToken is the associated secret/token used by ApiService middleware.
 //
-// This method mutates client state and therefore must not be called in
-// parallel with in-flight requests if strict thread-safety is required.
+// It is safe to call concurrently with in-flight requests, which keep the
+// credentials they were built with. To act for several profiles
+// concurrently, use WithProfileAuth instead.
 func (c *Client) SetAuth(proID, proToken string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 	c.proID = strings.TrimSpace(proID)
 	c.proToken = strings.TrimSpace(proToken)
 }
 
+// ClearAuth removes the credentials configured with SetAuth: subsequent
+// requests are sent without X-Pro-Id and X-Pro-Token headers (unless
+// their context carries WithProfileAuth credentials).
+func (c *Client) ClearAuth() {
+	c.SetAuth("", "")
+}
+
+// HasAuth reports whether both a profile id and a token are configured
+// with SetAuth. It does not check that the server accepts them (see
+// WithVerifiedAuth), nor credentials attached with WithProfileAuth.
+func (c *Client) HasAuth() bool {
+	proID, proToken := c.auth()
+	return proID != "" && proToken != ""
+}
+
+// auth returns the credentials configured with SetAuth.
+func (c *Client) auth() (proID, proToken string) {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.proID, c.proToken
+}
+
+// resolveProID returns the trimmed proID or, when proID is empty and
+// WithDefaultProIDFromAuth is set, the profile id of the call (see
+// WithProfileAuth) or else the one configured with SetAuth. The result is
+// empty when none is available.
+func (c *Client) resolveProID(ctx context.Context, proID string) string {
+	proID = strings.TrimSpace(proID)
+	if proID != "" || !c.defaultProIDFromAuth {
+		return proID
+	}
+	if auth, ok := profileAuthFromContext(ctx); ok {
+		return auth.proID
+	}
+	proID, _ = c.auth()
+	return proID
+}
+
 // BaseURL returns a copy of the base API URL used by the client.
 func (c *Client) BaseURL() url.URL {
 	return *c.baseURL
 }
 
+// BaseURLString returns the effective base URL as used to build request
+// URLs (query and fragment dropped, base path cleaned, no trailing
+// slash), for display in logs and UIs. A password in the URL is redacted.
+func (c *Client) BaseURLString() string {
+	u := c.normalizedBaseURL()
+	return u.Redacted()
+}
+
 // HTTPClient returns the underlying HTTP client. If the client is nil,
 // http.DefaultClient will be used automatically.
 func (c *Client) HTTPClient() *http.Client {
@@ -117,17 +249,149 @@ type APIError struct {
 	// raw body content or HTTP status text.
 	Message string
 
-	// Body holds the raw response body bytes as returned by the server.
+	// Details lists the entries of the JSON field "details" sent along
+	// with "error", e.g. one per invalid field of a validation error;
+	// nil when absent. Entries that are not strings are kept as JSON text.
+	Details []string
+
+	// Body holds the raw response body bytes as returned by the server,
+	// up to the error body limit (see WithMaxResponseBytes).
 	Body []byte
+
+	// Truncated reports whether the server sent more than Body holds.
+	// It is also set when reading the body failed (see ReadErr).
+	Truncated bool
+
+	// ReadErr is the error that interrupted reading the body, e.g. a
+	// deadline hit while the server stalled; Body then holds the bytes
+	// read before it. ReadErr is returned by Unwrap, so errors.Is
+	// matches it (context.DeadlineExceeded, ...).
+	ReadErr error
+
+	// RetryAfter is the delay requested by the Retry-After header
+	// (seconds or HTTP date); 0 when absent or invalid.
+	RetryAfter time.Duration
+
+	// Maintenance reports a 503 flagged by the server as planned
+	// maintenance, through a truthy X-Maintenance header or a JSON body
+	// with "maintenance": true. Such errors match ErrMaintenance.
+	Maintenance bool
+
+	// Elapsed is the time from sending the request until the error
+	// response was read.
+	Elapsed time.Duration
+}
+
+// Is makes errors.Is(err, ErrMaintenance) report maintenance responses.
+func (e *APIError) Is(target error) bool {
+	return target == ErrMaintenance && e.Maintenance
+}
+
+// Unwrap returns ReadErr, if any.
+func (e *APIError) Unwrap() error {
+	return e.ReadErr
 }
 
+// DefaultMaxErrorBodyBytes is the number of bytes read from the body of
+// a non-2xx response when WithMaxResponseBytes is not set.
+const DefaultMaxErrorBodyBytes = 64 * 1024
+
 // Error implements the error interface, providing a concise representation
 // of the HTTP status and error message.
 func (e *APIError) Error() string {
+	s := fmt.Sprintf("api error: status=%d", e.StatusCode)
 	if e.Message != "" {
-		return fmt.Sprintf("api error: status=%d message=%q", e.StatusCode, e.Message)
+		s += fmt.Sprintf(" message=%q", e.Message)
+	}
+	if len(e.Details) > 0 {
+		s += fmt.Sprintf(" details=%q", e.Details)
+	}
+	if e.ReadErr != nil {
+		s += fmt.Sprintf(" (read body: %v)", e.ReadErr)
+	}
+	return s
+}
+
+// newAPIError builds an *APIError from a non-2xx response and the (possibly
+// truncated) body bytes read from it.
+//
+// The message is extracted from JSON field "error" when possible; otherwise
+// it falls back to the raw body content or HTTP status text.
+func newAPIError(resp *http.Response, data []byte) *APIError {
+	var payload struct {
+		Error       string            `json:"error"`
+		Details     []json.RawMessage `json:"details"`
+		Maintenance bool              `json:"maintenance"`
+	}
+	_ = json.Unmarshal(data, &payload)
+
+	msg := strings.TrimSpace(payload.Error)
+	if msg == "" && len(data) > 0 {
+		msg = strings.TrimSpace(string(data))
+	}
+	if msg == "" {
+		msg = resp.Status
 	}
-	return fmt.Sprintf("api error: status=%d", e.StatusCode)
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    msg,
+		Details:    errorDetails(payload.Details),
+		Body:       data,
+		RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+		Maintenance: resp.StatusCode == http.StatusServiceUnavailable &&
+			(payload.Maintenance || isTruthyHeader(resp.Header.Get("X-Maintenance"))),
+	}
+}
+
+// errorDetails converts the "details" entries of an error body to
+// strings: JSON strings are unquoted, other values kept as JSON text.
+func errorDetails(raw []json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			out = append(out, s)
+			continue
+		}
+		out = append(out, string(r))
+	}
+	return out
+}
+
+// readAPIError reads the body of a non-2xx response, up to the error body
+// limit, and returns it as an *APIError whose Elapsed is measured from
+// start. The read is bound to the request context, so a stalled body
+// fails once the deadline passes; the APIError then carries the partial
+// body, is marked Truncated and wraps the read error as ReadErr.
+func (c *Client) readAPIError(resp *http.Response, start time.Time) error {
+	limit := c.maxResponseBytes
+	if limit <= 0 {
+		limit = DefaultMaxErrorBodyBytes
+	}
+	data, truncated, err := readLimited(resp.Body, limit)
+	apiErr := newAPIError(resp, data)
+	apiErr.Truncated = truncated || err != nil
+	apiErr.ReadErr = err
+	apiErr.Elapsed = time.Since(start)
+	return apiErr
+}
+
+// readLimited reads r to EOF, keeping at most limit bytes; limit <= 0
+// means no limit. truncated reports whether r had more data.
+func readLimited(r io.Reader, limit int64) (data []byte, truncated bool, err error) {
+	if limit <= 0 {
+		data, err = io.ReadAll(r)
+		return data, false, err
+	}
+	data, err = io.ReadAll(io.LimitReader(r, limit+1))
+	if int64(len(data)) > limit {
+		return data[:limit], true, err
+	}
+	return data, false, err
 }
 
 // newRequest builds an *http.Request for the given method and relative path,
@@ -138,7 +402,9 @@ func (e *APIError) Error() string {
 //   - "api/facts/items/snapshot"
 //
 // The function ensures that the final URL is baseURL + path, preserving
-// any base path component present in baseURL.
+// any base path component present in baseURL. The path is normalized
+// (duplicate and trailing slashes removed) unless ctx carries
+// PreserveTrailingSlash.
 func (c *Client) newRequest(
 	ctx context.Context,
 	method string,
@@ -149,6 +415,129 @@ func (c *Client) newRequest(
 	if ctx == nil {
 		return nil, errors.New("ctx must not be nil")
 	}
+
+	u, err := c.buildURL(pathOrEndpoint, query)
+	if err != nil {
+		return nil, err
+	}
+	if preservesTrailingSlash(ctx) && strings.HasSuffix(strings.TrimSpace(pathOrEndpoint), "/") && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	return req, nil
+}
+
+// newStreamingRequest is newRequest for bodies produced on the fly:
+// newBody is called once for the first attempt and is installed as
+// req.GetBody, so that every retry streams a fresh copy.
+//
+// Bodies passed to newRequest as *bytes.Buffer, *bytes.Reader or
+// *strings.Reader are replayable without a factory (net/http sets
+// GetBody for them); any other reader can be sent only once.
+func (c *Client) newStreamingRequest(
+	ctx context.Context,
+	method string,
+	pathOrEndpoint string,
+	query url.Values,
+	newBody func() (io.ReadCloser, error),
+) (*http.Request, error) {
+	body, err := newBody()
+	if err != nil {
+		return nil, fmt.Errorf("create request body: %w", err)
+	}
+	req, err := c.newRequest(ctx, method, pathOrEndpoint, query, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.GetBody = newBody
+	return req, nil
+}
+
+// rewindRequest returns a copy of req, which has already been sent, that
+// can be sent again: its body is recreated through req.GetBody. It fails
+// with ErrBodyNotReplayable instead of letting a retry go out with an
+// empty or partially consumed body.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrBodyNotReplayable)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: recreate body: %w", req.Method, req.URL.Path, err)
+	}
+	next.Body = body
+	return next, nil
+}
+
+// closeRequestBody closes the body of a request that will not be sent.
+// Bodies recreated by GetBody may be pipes fed by a goroutine, which only
+// the transport would otherwise close.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// URLFor returns the absolute URL a request to endpoint with the given
+// query would be sent to, using exactly the same joining rules as the
+// client's own methods. It is meant for logging, debugging and tests.
+//
+// endpoint is a relative path such as "/api/facts/items/snapshot";
+// query may be nil.
+func (c *Client) URLFor(endpoint string, query url.Values) (string, error) {
+	u, err := c.buildURL(endpoint, query)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// normalizedBaseURL returns the base URL that every request URL is built
+// upon: the base path cleaned and stripped of its trailing slash.
+func (c *Client) normalizedBaseURL() url.URL {
+	u := *c.baseURL
+	u.RawPath = ""
+	if p := strings.TrimRight(u.Path, "/"); p != "" {
+		u.Path = path.Clean(p)
+	} else {
+		u.Path = ""
+	}
+	return u
+}
+
+// LimitAll can be passed wherever a limit is accepted (GetFactsSnapshot,
+// GetFactsUpdates, MatchesFilter.Limit, ...) to explicitly request as many
+// items as the server allows. It is sent as limit=0, which the server
+// treats as "no client limit" and still clamps to its own maximum page
+// size, so a response may hold fewer items than exist.
+//
+// A limit of 0 instead omits the parameter and lets the server apply its
+// default page size, which is usually smaller than its maximum. Other
+// negative limits are omitted as well.
+const LimitAll = -1
+
+// setLimit adds limit to q following the LimitAll convention.
+func setLimit(q url.Values, limit int) {
+	switch {
+	case limit > 0:
+		q.Set("limit", strconv.Itoa(limit))
+	case limit == LimitAll:
+		q.Set("limit", "0")
+	}
+}
+
+// buildURL joins the base URL with pathOrEndpoint and attaches query.
+// Any base path component present in baseURL is preserved.
+func (c *Client) buildURL(pathOrEndpoint string, query url.Values) (*url.URL, error) {
 	if c.baseURL == nil {
 		return nil, errors.New("client baseURL is not initialized")
 	}
@@ -158,19 +547,13 @@ func (c *Client) newRequest(
 		relPath = "/" + relPath
 	}
 
-	u := *c.baseURL
-	u.Path = strings.TrimRight(c.baseURL.Path, "/")
+	u := c.normalizedBaseURL()
 	u.Path = path.Join(u.Path, relPath)
 
 	if query != nil {
 		u.RawQuery = query.Encode()
 	}
-
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	return req, nil
+	return &u, nil
 }
 
 // applyHeaders merges base headers (including X-Pro-Id / X-Pro-Token)
@@ -178,6 +561,10 @@ func (c *Client) newRequest(
 //
 // extra may be nil. If not nil, its contents are copied into a new map
 // so that callers are free to reuse their header instances.
+//
+// Accept defaults to application/json. Methods that do not expect JSON
+// (streams, raw downloads) suppress the default by setting their own
+// Accept in extra, e.g. text/event-stream or */*.
 func (c *Client) applyHeaders(req *http.Request, extra http.Header) {
 	merged := make(http.Header, len(extra)+2)
 
@@ -187,11 +574,15 @@ func (c *Client) applyHeaders(req *http.Request, extra http.Header) {
 		merged[k] = dst
 	}
 
-	if c.proID != "" {
-		merged.Set("X-Pro-Id", c.proID)
+	proID, proToken := c.auth()
+	if auth, ok := profileAuthFromContext(req.Context()); ok {
+		proID, proToken = auth.proID, auth.token
+	}
+	if proID != "" {
+		merged.Set("X-Pro-Id", proID)
 	}
-	if c.proToken != "" {
-		merged.Set("X-Pro-Token", c.proToken)
+	if proToken != "" {
+		merged.Set("X-Pro-Token", proToken)
 	}
 	if merged.Get("Accept") == "" {
 		merged.Set("Accept", "application/json")
@@ -203,48 +594,84 @@ func (c *Client) applyHeaders(req *http.Request, extra http.Header) {
 // doJSON executes a prepared HTTP request, validates the response status,
 // and if v is non-nil, unmarshals the response JSON into v.
 //
-// On non-2xx responses, an *APIError is returned.
+// On non-2xx responses, an *APIError is returned. Response details are
+// recorded in the ResponseMeta attached to the request context, if any.
 func (c *Client) doJSON(req *http.Request, v any) error {
-	resp, err := c.HTTPClient().Do(req)
+	_, err := c.doJSONStatus(req, v)
+	return err
+}
+
+// doJSONStatus is doJSON that also returns the HTTP status code of a
+// successful response, for methods that treat some 2xx codes specially.
+func (c *Client) doJSONStatus(req *http.Request, v any) (int, error) {
+	data, status, err := c.doRaw(req)
 	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
+		return 0, err
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read response body: %w", err)
+	if v == nil || len(data) == 0 {
+		return status, nil
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var payload struct {
-			Error string `json:"error"`
-		}
-		_ = json.Unmarshal(data, &payload)
+	if err := json.Unmarshal(data, v); err != nil {
+		return 0, fmt.Errorf("decode JSON response: %w", err)
+	}
+	return status, nil
+}
 
-		msg := strings.TrimSpace(payload.Error)
-		if msg == "" && len(data) > 0 {
-			msg = strings.TrimSpace(string(data))
-		}
-		if msg == "" {
-			msg = resp.Status
-		}
+// doRaw executes a prepared HTTP request and returns the full response
+// body and status code of a 2xx response. On non-2xx responses, an
+// *APIError is returned.
+//
+// A 2xx body larger than WithMaxResponseBytes fails with an error
+// matching ErrResponseTooLarge. A request whose context has no deadline is
+// bounded by the client's maximum request duration, across all attempts
+// when WithRetry is set.
+func (c *Client) doRaw(req *http.Request) ([]byte, int, error) {
+	req, cancel := c.boundRequest(req)
+	defer cancel()
+	if c.retry != nil {
+		return c.doRetry(req)
+	}
+	return c.doOnce(req)
+}
 
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    msg,
-			Body:       data,
-		}
+// boundRequest applies the maximum request duration (see
+// WithMaxRequestDuration) to a non-streaming request whose context has no
+// deadline. The returned cancel function must be called once the
+// response has been consumed.
+func (c *Client) boundRequest(req *http.Request) (*http.Request, context.CancelFunc) {
+	if _, ok := req.Context().Deadline(); ok || c.maxRequestDuration <= 0 {
+		return req, func() {}
 	}
+	ctx, cancel := context.WithTimeout(req.Context(), c.maxRequestDuration)
+	return req.WithContext(ctx), cancel
+}
 
-	if v == nil || len(data) == 0 {
-		return nil
+// doOnce makes a single attempt of doRaw.
+func (c *Client) doOnce(req *http.Request) ([]byte, int, error) {
+	start := time.Now()
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, 0, err
 	}
+	defer resp.Body.Close()
+	captureResponseMeta(req.Context(), resp)
+	c.logWarnings(req.Context(), resp)
+	c.recordRateLimit(resp)
 
-	if err := json.Unmarshal(data, v); err != nil {
-		return fmt.Errorf("decode JSON response: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, 0, c.readAPIError(resp, start)
 	}
-	return nil
+
+	data, truncated, err := readLimited(resp.Body, c.maxResponseBytes)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response body: %w", err)
+	}
+	if truncated {
+		return nil, 0, fmt.Errorf("read response body: %w (limit %d bytes)", ErrResponseTooLarge, c.maxResponseBytes)
+	}
+	return data, resp.StatusCode, nil
 }
 
 // CreateProWallet issues a POST request to /api/crypto/pro-wallet/create.
@@ -261,6 +688,7 @@ func (c *Client) CreateProWallet(
 	ctx context.Context,
 	manaxKey string,
 ) (*CreateProWalletResponse, error) {
+	ctx = withOperation(ctx, "CreateProWallet")
 	req, err := c.newRequest(ctx, http.MethodPost, "/api/crypto/pro-wallet/create", nil, nil)
 	if err != nil {
 		return nil, err
@@ -290,6 +718,7 @@ func (c *Client) VerifyProWallet(
 	proID string,
 	token string,
 ) (*VerifyProWalletResponse, error) {
+	ctx = withOperation(ctx, "VerifyProWallet")
 	proID = strings.TrimSpace(proID)
 	token = strings.TrimSpace(token)
 
@@ -318,6 +747,25 @@ func (c *Client) VerifyProWallet(
 	return &out, nil
 }
 
+// WithVerifiedAuth verifies (proID, token) with VerifyProWallet and, only
+// if the server reports the token as valid, installs them with SetAuth.
+//
+// If the token is rejected, the configured auth is left unchanged and an
+// error matching ErrTokenInvalid is returned. Request failures are
+// returned as-is, also without touching the configured auth. Like
+// SetAuth, it must not run concurrently with in-flight requests.
+func (c *Client) WithVerifiedAuth(ctx context.Context, proID, token string) error {
+	res, err := c.VerifyProWallet(ctx, proID, token)
+	if err != nil {
+		return fmt.Errorf("WithVerifiedAuth: %w", err)
+	}
+	if !res.Valid {
+		return fmt.Errorf("WithVerifiedAuth: %w (proId %s)", ErrTokenInvalid, strings.TrimSpace(proID))
+	}
+	c.SetAuth(proID, token)
+	return nil
+}
+
 // UploadSpeechAudio uploads a single audio chunk via
 // POST /api/speech/upload (multipart/form-data).
 //
@@ -327,17 +775,26 @@ func (c *Client) VerifyProWallet(
 //   - sessionId  : logical session id, grouping multiple chunks.
 //   - chunkIndex : index of the chunk within the session (0-based).
 //   - sampleRate : optional sample rate in Hz.
+//   - final      : "true" on the last chunk of the session (optional).
 //
 // The server responds with SpeechUploadResponse describing stored paths,
 // effective sample rate, transcript (if already available) and other metadata.
+//
+// With WithGzipAudioUploads, large chunks are sent gzip-compressed
+// (Content-Encoding: gzip) and streamed instead of buffered.
 func (c *Client) UploadSpeechAudio(
 	ctx context.Context,
 	in UploadSpeechAudioRequest,
 ) (*SpeechUploadResponse, error) {
+	ctx = withOperation(ctx, "UploadSpeechAudio")
+	if ctx == nil {
+		return nil, errors.New("UploadSpeechAudio: ctx must not be nil")
+	}
 	if in.Audio == nil {
 		return nil, errors.New("UploadSpeechAudio: Audio must not be nil")
 	}
-	if strings.TrimSpace(in.ProID) == "" {
+	in.ProID = c.resolveProID(ctx, in.ProID)
+	if in.ProID == "" {
 		return nil, errors.New("UploadSpeechAudio: ProID must not be empty")
 	}
 	if strings.TrimSpace(in.SessionID) == "" {
@@ -346,56 +803,171 @@ func (c *Client) UploadSpeechAudio(
 	if in.ChunkIndex < 0 {
 		return nil, errors.New("UploadSpeechAudio: ChunkIndex must be >= 0")
 	}
+	if err := c.checkSampleRate(in.SampleRate); err != nil {
+		return nil, fmt.Errorf("UploadSpeechAudio: %w", err)
+	}
+
+	release, err := c.acquireUpload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if c.gzipAudio && audioAtLeast(in.Audio, c.gzipAudioMinBytes) {
+		return c.uploadSpeechAudioGzip(ctx, in)
+	}
 
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
+	// The copy may take long for large or slow sources; make it abort
+	// promptly on cancellation instead of waiting for the HTTP layer.
+	if err := writeSpeechAudioForm(ctx, writer, in); err != nil {
+		buf.Reset()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/speech/upload", nil, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	h.Set("Content-Type", writer.FormDataContentType())
+	c.applyHeaders(req, h)
+
+	var out SpeechUploadResponse
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	if err := c.checkProID("UploadSpeechAudio", in.ProID, out.ProID); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// uploadSpeechAudioGzip is UploadSpeechAudio for WithGzipAudioUploads:
+// the multipart body is gzip-compressed on the fly into a pipe, so
+// neither the audio nor the compressed form is held in memory.
+func (c *Client) uploadSpeechAudioGzip(
+	ctx context.Context,
+	in UploadSpeechAudioRequest,
+) (*SpeechUploadResponse, error) {
+	pr, pw := io.Pipe()
+	zw := gzip.NewWriter(pw)
+	writer := multipart.NewWriter(zw)
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/speech/upload", nil, pr)
+	if err != nil {
+		pw.Close()
+		return nil, err
+	}
+
+	// The transport closes pr when it is done with the body, but a request
+	// failing before it is sent (signer, middleware) never reaches the
+	// transport: closing pr on return stops this goroutine in every case.
+	defer pr.Close()
+	go func() {
+		err := writeSpeechAudioForm(ctx, writer, in)
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	h := http.Header{}
+	h.Set("Content-Type", writer.FormDataContentType())
+	h.Set("Content-Encoding", "gzip")
+	c.applyHeaders(req, h)
+
+	var out SpeechUploadResponse
+	if err := c.doJSON(req, &out); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if err := c.checkProID("UploadSpeechAudio", in.ProID, out.ProID); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// writeSpeechAudioForm writes the multipart form of UploadSpeechAudio
+// for in to writer and closes it.
+func writeSpeechAudioForm(ctx context.Context, writer *multipart.Writer, in UploadSpeechAudioRequest) error {
 	fileName := in.FileName
 	if strings.TrimSpace(fileName) == "" {
 		fileName = "audio"
 	}
 	part, err := writer.CreateFormFile("audio", fileName)
 	if err != nil {
-		return nil, fmt.Errorf("create form file: %w", err)
+		return fmt.Errorf("create form file: %w", err)
 	}
 
-	if _, err := io.Copy(part, in.Audio); err != nil {
-		return nil, fmt.Errorf("copy audio: %w", err)
+	if _, err := io.Copy(part, newCtxReader(ctx, in.Audio)); err != nil {
+		return fmt.Errorf("copy audio: %w", err)
 	}
 
 	if err := writer.WriteField("proId", strings.TrimSpace(in.ProID)); err != nil {
-		return nil, fmt.Errorf("write proId: %w", err)
+		return fmt.Errorf("write proId: %w", err)
 	}
 	if err := writer.WriteField("sessionId", strings.TrimSpace(in.SessionID)); err != nil {
-		return nil, fmt.Errorf("write sessionId: %w", err)
+		return fmt.Errorf("write sessionId: %w", err)
 	}
 	if err := writer.WriteField("chunkIndex", strconv.Itoa(in.ChunkIndex)); err != nil {
-		return nil, fmt.Errorf("write chunkIndex: %w", err)
+		return fmt.Errorf("write chunkIndex: %w", err)
 	}
 	if in.SampleRate > 0 {
 		if err := writer.WriteField("sampleRate", strconv.Itoa(in.SampleRate)); err != nil {
-			return nil, fmt.Errorf("write sampleRate: %w", err)
+			return fmt.Errorf("write sampleRate: %w", err)
+		}
+	}
+	if in.Final {
+		if err := writer.WriteField("final", "true"); err != nil {
+			return fmt.Errorf("write final: %w", err)
 		}
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("finalize multipart body: %w", err)
+		return fmt.Errorf("finalize multipart body: %w", err)
 	}
+	return nil
+}
 
-	req, err := c.newRequest(ctx, http.MethodPost, "/api/speech/upload", nil, &buf)
-	if err != nil {
-		return nil, err
+// acquireUpload waits for an upload slot when WithMaxConcurrentUploads is
+// set. It returns ctx.Err() if ctx is done first; otherwise the returned
+// function must be called to free the slot.
+func (c *Client) acquireUpload(ctx context.Context) (release func(), err error) {
+	if c.uploadSem == nil {
+		return func() {}, nil
 	}
+	select {
+	case c.uploadSem <- struct{}{}:
+		return func() { <-c.uploadSem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
 
-	h := http.Header{}
-	h.Set("Content-Type", writer.FormDataContentType())
-	c.applyHeaders(req, h)
-
-	var out SpeechUploadResponse
-	if err := c.doJSON(req, &out); err != nil {
-		return nil, err
+// UploadSpeechAudioCreated is UploadSpeechAudio for idempotent upload
+// loops: created reports whether the call stored a new chunk, i.e. the
+// server answered Ok and the chunk had not Existed before.
+//
+// resp is returned as-is for callers needing the other fields; it is nil
+// only when err is not nil.
+func (c *Client) UploadSpeechAudioCreated(
+	ctx context.Context,
+	in UploadSpeechAudioRequest,
+) (created bool, resp *SpeechUploadResponse, err error) {
+	resp, err = c.UploadSpeechAudio(ctx, in)
+	if err != nil {
+		return false, nil, err
 	}
-	return &out, nil
+	return resp.Ok && !resp.Existed, resp, nil
 }
 
 // UploadSpeechText sends a text segment associated with a speech chunk
@@ -411,11 +983,16 @@ func (c *Client) UploadSpeechAudio(
 // The exact response shape depends on the server implementation. This client
 // deliberately exposes it as opaque JSON (json.RawMessage) to avoid
 // hardcoding speculative fields.
+//
+// Texts larger than 1 MiB are encoded while being sent (chunked transfer
+// encoding), so that huge transcripts are not duplicated in memory.
 func (c *Client) UploadSpeechText(
 	ctx context.Context,
 	in UploadSpeechTextRequest,
 ) (*UploadSpeechTextResponse, error) {
-	if strings.TrimSpace(in.ProID) == "" {
+	ctx = withOperation(ctx, "UploadSpeechText")
+	in.ProID = c.resolveProID(ctx, in.ProID)
+	if in.ProID == "" {
 		return nil, errors.New("UploadSpeechText: ProID must not be empty")
 	}
 	if strings.TrimSpace(in.SessionID) == "" {
@@ -428,15 +1005,23 @@ func (c *Client) UploadSpeechText(
 		return nil, errors.New("UploadSpeechText: Text must not be empty")
 	}
 
-	payload, err := json.Marshal(in)
-	if err != nil {
-		return nil, fmt.Errorf("marshal UploadSpeechTextRequest: %w", err)
+	if len(in.Text) <= streamBodyThreshold {
+		raw, err := PostJSON[UploadSpeechTextRequest, json.RawMessage](ctx, c, "/api/speech/text", nil, in)
+		if err != nil {
+			return nil, err
+		}
+		return &UploadSpeechTextResponse{Raw: *raw}, nil
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, "/api/speech/text", nil, bytes.NewReader(payload))
+	req, err := c.newStreamingRequest(ctx, http.MethodPost, "/api/speech/text", nil, func() (io.ReadCloser, error) {
+		return newSpeechTextBody(in), nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	// A request failing before it reaches the transport (signer,
+	// middleware) leaves the body open; closing it stops the encoder.
+	defer req.Body.Close()
 
 	h := http.Header{}
 	h.Set("Content-Type", "application/json")
@@ -446,7 +1031,6 @@ func (c *Client) UploadSpeechText(
 	if err := c.doJSON(req, &raw); err != nil {
 		return nil, err
 	}
-
 	return &UploadSpeechTextResponse{Raw: raw}, nil
 }
 
@@ -458,9 +1042,15 @@ func (c *Client) GetSpeechStatusByID(
 	ctx context.Context,
 	id int64,
 ) (*SpeechStatusResponse, error) {
+	ctx = withOperation(ctx, "GetSpeechStatusByID")
 	if id <= 0 {
 		return nil, errors.New("GetSpeechStatusByID: id must be > 0")
 	}
+	if c.statusCache != nil {
+		if cached, ok := c.statusCache.get(id); ok {
+			return cached, nil
+		}
+	}
 
 	q := url.Values{}
 	q.Set("id", strconv.FormatInt(id, 10))
@@ -474,7 +1064,10 @@ func (c *Client) GetSpeechStatusByID(
 
 	var out SpeechStatusResponse
 	if err := c.doJSON(req, &out); err != nil {
-		return nil, err
+		return nil, c.mapNotFound("GetSpeechStatusByID", err)
+	}
+	if c.statusCache != nil {
+		c.statusCache.put(id, &out)
 	}
 	return &out, nil
 }
@@ -483,14 +1076,16 @@ func (c *Client) GetSpeechStatusByID(
 // (proId, sessionId, chunkIndex). This is used when the caller does not
 // know the internal numeric id but has logical identifiers.
 //
-// proID is optional from server perspective; if empty, the server may fall
-// back to "p_anon" or another default.
+// proID is optional from server perspective; if it is empty (and not
+// resolved from the auth profile, see WithDefaultProIDFromAuth), the
+// server may fall back to "p_anon" or another default.
 func (c *Client) GetSpeechStatusByKey(
 	ctx context.Context,
 	proID string,
 	sessionID string,
 	chunkIndex int,
 ) (*SpeechStatusResponse, error) {
+	ctx = withOperation(ctx, "GetSpeechStatusByKey")
 	sessionID = strings.TrimSpace(sessionID)
 	if sessionID == "" {
 		return nil, errors.New("GetSpeechStatusByKey: sessionID must not be empty")
@@ -499,9 +1094,11 @@ func (c *Client) GetSpeechStatusByKey(
 		return nil, errors.New("GetSpeechStatusByKey: chunkIndex must be >= 0")
 	}
 
+	proID = c.resolveProID(ctx, proID)
+
 	q := url.Values{}
-	if strings.TrimSpace(proID) != "" {
-		q.Set("proId", strings.TrimSpace(proID))
+	if proID != "" {
+		q.Set("proId", proID)
 	}
 	q.Set("sessionId", sessionID)
 	q.Set("chunkIndex", strconv.Itoa(chunkIndex))
@@ -515,7 +1112,7 @@ func (c *Client) GetSpeechStatusByKey(
 
 	var out SpeechStatusResponse
 	if err := c.doJSON(req, &out); err != nil {
-		return nil, err
+		return nil, c.mapNotFound("GetSpeechStatusByKey", err)
 	}
 	return &out, nil
 }
@@ -530,15 +1127,50 @@ func (c *Client) GetFactsSnapshot(
 	proID string,
 	limit int,
 ) (*FactsItemsResponse, error) {
-	proID = strings.TrimSpace(proID)
+	return c.getFactsSnapshot(ctx, "GetFactsSnapshot", FactsSnapshotRequest{
+		ProID: proID,
+		Limit: limit,
+	})
+}
+
+// GetFactsSnapshotWithRequest is the structured variant of GetFactsSnapshot
+// supporting additional parameters such as a text search Query.
+//
+// When in.Query is set it is sent as q=<query>. Servers that do not support
+// text search ignore the parameter, so the client additionally filters the
+// returned items by case-insensitive substring match on FactText. As a
+// consequence:
+//   - the result never contains non-matching items, whatever the server does;
+//   - with a server lacking search, Limit applies before the client-side
+//     filter, so fewer than Limit items may be returned even though more
+//     matching facts exist;
+//   - the cursor always reflects the server window, not the filtered items.
+func (c *Client) GetFactsSnapshotWithRequest(
+	ctx context.Context,
+	in FactsSnapshotRequest,
+) (*FactsItemsResponse, error) {
+	return c.getFactsSnapshot(ctx, "GetFactsSnapshotWithRequest", in)
+}
+
+// getFactsSnapshot implements GetFactsSnapshot and
+// GetFactsSnapshotWithRequest; op is used as error message prefix.
+func (c *Client) getFactsSnapshot(
+	ctx context.Context,
+	op string,
+	in FactsSnapshotRequest,
+) (*FactsItemsResponse, error) {
+	ctx = withOperation(ctx, op)
+	proID := c.resolveProID(ctx, in.ProID)
 	if proID == "" {
-		return nil, errors.New("GetFactsSnapshot: proID must not be empty")
+		return nil, fmt.Errorf("%s: proID must not be empty", op)
 	}
+	query := strings.TrimSpace(in.Query)
 
 	q := url.Values{}
 	q.Set("proId", proID)
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
+	setLimit(q, in.Limit)
+	if query != "" {
+		q.Set("q", query)
 	}
 
 	req, err := c.newRequest(ctx, http.MethodGet, "/api/facts/items/snapshot", q, nil)
@@ -552,9 +1184,29 @@ func (c *Client) GetFactsSnapshot(
 	if err := c.doJSON(req, &out); err != nil {
 		return nil, err
 	}
+	if err := c.checkProID(op, proID, out.ProID); err != nil {
+		return nil, err
+	}
+
+	if query != "" {
+		out.Items = filterFactsByText(out.Items, query)
+	}
 	return &out, nil
 }
 
+// filterFactsByText returns the items whose FactText contains query,
+// compared case-insensitively. The input slice is not modified.
+func filterFactsByText(items []FactItem, query string) []FactItem {
+	needle := strings.ToLower(query)
+	out := make([]FactItem, 0, len(items))
+	for _, it := range items {
+		if strings.Contains(strings.ToLower(it.FactText), needle) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
 // GetFactsUpdates invokes GET /api/facts/items/updates using the provided
 // cursor state and desired limit.
 //
@@ -573,7 +1225,8 @@ func (c *Client) GetFactsUpdates(
 	sinceID int64,
 	limit int,
 ) (*FactsUpdatesResponse, error) {
-	proID = strings.TrimSpace(proID)
+	ctx = withOperation(ctx, "GetFactsUpdates")
+	proID = c.resolveProID(ctx, proID)
 	if proID == "" {
 		return nil, errors.New("GetFactsUpdates: proID must not be empty")
 	}
@@ -587,22 +1240,43 @@ func (c *Client) GetFactsUpdates(
 		q.Set("sinceUpdatedUtc", sinceUpdatedUtc.UTC().Format(time.RFC3339))
 	}
 	q.Set("sinceId", strconv.FormatInt(sinceID, 10))
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
-	}
+	setLimit(q, limit)
 
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/facts/items/updates", q, nil)
+	out, err := GetJSON[FactsUpdatesResponse](ctx, c, "/api/facts/items/updates", q)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.checkProID("GetFactsUpdates", proID, out.ProID); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-	c.applyHeaders(req, nil)
+// GetRecentFacts returns the facts of proID updated within the last
+// `within` (for example time.Hour), without requiring a cursor. It calls
+// GetFactsUpdates with sinceUpdatedUtc = now - within and sinceId = 0.
+//
+// The cut-off is computed from the local clock and sent with second
+// precision, while the server compares it against its own timestamps: if
+// the two clocks disagree, facts near the boundary may be included or
+// missed. Use a cursor (GetFactsSnapshot + GetFactsUpdates) when exact
+// completeness matters. At most limit items are returned (0 = server
+// default).
+func (c *Client) GetRecentFacts(
+	ctx context.Context,
+	proID string,
+	within time.Duration,
+	limit int,
+) ([]FactItem, error) {
+	if within <= 0 {
+		return nil, errors.New("GetRecentFacts: within must be > 0")
+	}
 
-	var out FactsUpdatesResponse
-	if err := c.doJSON(req, &out); err != nil {
+	upd, err := c.GetFactsUpdates(ctx, proID, time.Now().Add(-within), 0, limit)
+	if err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return upd.Items, nil
 }
 
 // PatchFactReviewStatus issues PATCH /api/facts/items/{id}/review-status
@@ -613,32 +1287,68 @@ func (c *Client) GetFactsUpdates(
 //   - "ok"
 //   - "not"
 //   - null (cleared; represented here by empty string).
+//
+// A 204 No Content answer is a success and yields Code "ok", like the
+// JSON answer of servers that send a body.
 func (c *Client) PatchFactReviewStatus(
 	ctx context.Context,
 	proID string,
 	id int64,
 	reviewStatus string,
 ) (*PatchReviewStatusResponse, error) {
-	proID = strings.TrimSpace(proID)
+	return c.patchFactReviewStatus(ctx, "PatchFactReviewStatus", PatchFactReviewStatusRequest{
+		ProID:        proID,
+		ID:           id,
+		ReviewStatus: reviewStatus,
+	})
+}
+
+// PatchFactReviewStatusWithRequest is the structured variant of
+// PatchFactReviewStatus supporting optimistic concurrency.
+//
+// When in.ExpectedUpdatedUTC is set it is sent as
+//
+//	If-Match: "<ExpectedUpdatedUTC in RFC3339Nano, UTC>"
+//
+// and the server must reject the patch if the fact has changed since.
+// Such a rejection (409 Conflict or 412 Precondition Failed) is returned
+// as an error matching ErrConflict; the *APIError remains available via
+// errors.As. Callers typically re-read the fact and decide again.
+func (c *Client) PatchFactReviewStatusWithRequest(
+	ctx context.Context,
+	in PatchFactReviewStatusRequest,
+) (*PatchReviewStatusResponse, error) {
+	return c.patchFactReviewStatus(ctx, "PatchFactReviewStatusWithRequest", in)
+}
+
+// patchFactReviewStatus implements PatchFactReviewStatus and
+// PatchFactReviewStatusWithRequest; op is used as error message prefix.
+func (c *Client) patchFactReviewStatus(
+	ctx context.Context,
+	op string,
+	in PatchFactReviewStatusRequest,
+) (*PatchReviewStatusResponse, error) {
+	ctx = withOperation(ctx, op)
+	proID := c.resolveProID(ctx, in.ProID)
 	if proID == "" {
-		return nil, errors.New("PatchFactReviewStatus: proID must not be empty")
+		return nil, fmt.Errorf("%s: proID must not be empty", op)
 	}
-	if id <= 0 {
-		return nil, errors.New("PatchFactReviewStatus: id must be > 0")
+	if in.ID <= 0 {
+		return nil, fmt.Errorf("%s: id must be > 0", op)
 	}
 
 	q := url.Values{}
 	q.Set("proId", proID)
 
 	body := PatchReviewStatusRequest{
-		ReviewStatus: strings.TrimSpace(reviewStatus),
+		ReviewStatus: encodeReviewStatus(in.ReviewStatus),
 	}
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("marshal PatchReviewStatusRequest: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("/api/facts/items/%d/review-status", id)
+	endpoint := fmt.Sprintf("/api/facts/items/%d/review-status", in.ID)
 	req, err := c.newRequest(ctx, http.MethodPatch, endpoint, q, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
@@ -646,11 +1356,18 @@ func (c *Client) PatchFactReviewStatus(
 
 	h := http.Header{}
 	h.Set("Content-Type", "application/json")
+	if !in.ExpectedUpdatedUTC.IsZero() {
+		h.Set("If-Match", strconv.Quote(in.ExpectedUpdatedUTC.UTC().Format(time.RFC3339Nano)))
+	}
 	c.applyHeaders(req, h)
 
 	var out PatchReviewStatusResponse
-	if err := c.doJSON(req, &out); err != nil {
-		return nil, err
+	status, err := c.doJSONStatus(req, &out)
+	if err != nil {
+		return nil, mapConflict(op, err)
+	}
+	if status == http.StatusNoContent {
+		out = PatchReviewStatusResponse{Code: "ok"}
 	}
 	return &out, nil
 }
@@ -674,42 +1391,75 @@ func (c *Client) GetMatchesSnapshot(
 	minRationaleLength int,
 	maxRationaleLength int,
 ) (*MatchesItemsResponse, error) {
-	proID = strings.TrimSpace(proID)
+	return c.getMatchesSnapshot(ctx, "GetMatchesSnapshot", MatchesSnapshotRequest{
+		ProID:     proID,
+		Direction: direction,
+		MatchesFilter: MatchesFilter{
+			MinScore:           minScore,
+			Limit:              limit,
+			MinRationaleLength: minRationaleLength,
+			MaxRationaleLength: maxRationaleLength,
+		},
+	})
+}
+
+// GetMatchesSnapshotWithRequest is the structured variant of
+// GetMatchesSnapshot supporting additional parameters such as Sort.
+//
+// An unknown Sort value is rejected before any request is sent. The sort
+// order only affects the snapshot window; the returned cursor keeps its
+// (updatedUtc, id) meaning for GetMatchesUpdates and StreamMatches.
+func (c *Client) GetMatchesSnapshotWithRequest(
+	ctx context.Context,
+	in MatchesSnapshotRequest,
+) (*MatchesItemsResponse, error) {
+	return c.getMatchesSnapshot(ctx, "GetMatchesSnapshotWithRequest", in)
+}
+
+// getMatchesSnapshot implements GetMatchesSnapshot and
+// GetMatchesSnapshotWithRequest; op is used as error message prefix.
+func (c *Client) getMatchesSnapshot(
+	ctx context.Context,
+	op string,
+	in MatchesSnapshotRequest,
+) (*MatchesItemsResponse, error) {
+	ctx = withOperation(ctx, op)
+	proID := c.resolveProID(ctx, in.ProID)
 	if proID == "" {
-		return nil, errors.New("GetMatchesSnapshot: proID must not be empty")
+		return nil, fmt.Errorf("%s: proID must not be empty", op)
+	}
+	if in.Direction == "" {
+		return nil, fmt.Errorf("%s: direction must not be empty", op)
 	}
-	if direction == "" {
-		return nil, errors.New("GetMatchesSnapshot: direction must not be empty")
+	if !in.Sort.valid() {
+		return nil, fmt.Errorf("%s: unsupported sort %q", op, in.Sort)
 	}
 
 	q := url.Values{}
 	q.Set("proId", proID)
-	q.Set("direction", string(direction))
-	if minScore > 0 {
-		q.Set("minScore", strconv.FormatFloat(minScore, 'f', -1, 64))
+	q.Set("direction", in.Direction.Query())
+	if in.MinScore > 0 {
+		q.Set("minScore", strconv.FormatFloat(in.MinScore, 'f', -1, 64))
 	}
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
+	setLimit(q, in.Limit)
+	if in.MinRationaleLength > 0 {
+		q.Set("minRationaleLength", strconv.Itoa(in.MinRationaleLength))
 	}
-	if minRationaleLength > 0 {
-		q.Set("minRationaleLength", strconv.Itoa(minRationaleLength))
+	if in.MaxRationaleLength > 0 {
+		q.Set("maxRationaleLength", strconv.Itoa(in.MaxRationaleLength))
 	}
-	if maxRationaleLength > 0 {
-		q.Set("maxRationaleLength", strconv.Itoa(maxRationaleLength))
+	if in.Sort != "" {
+		q.Set("sort", string(in.Sort))
 	}
 
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/matches/items/snapshot", q, nil)
+	out, err := GetJSON[MatchesItemsResponse](ctx, c, "/api/matches/items/snapshot", q)
 	if err != nil {
 		return nil, err
 	}
-
-	c.applyHeaders(req, nil)
-
-	var out MatchesItemsResponse
-	if err := c.doJSON(req, &out); err != nil {
+	if err := c.checkProID(op, proID, out.ProID); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return out, nil
 }
 
 // GetMatchesUpdates calls GET /api/matches/items/updates to retrieve
@@ -735,7 +1485,8 @@ func (c *Client) GetMatchesUpdates(
 	minRationaleLength int,
 	maxRationaleLength int,
 ) (*MatchesUpdatesResponse, error) {
-	proID = strings.TrimSpace(proID)
+	ctx = withOperation(ctx, "GetMatchesUpdates")
+	proID = c.resolveProID(ctx, proID)
 	if proID == "" {
 		return nil, errors.New("GetMatchesUpdates: proID must not be empty")
 	}
@@ -746,7 +1497,7 @@ func (c *Client) GetMatchesUpdates(
 	q := url.Values{}
 	q.Set("proId", proID)
 	if direction != "" {
-		q.Set("direction", string(direction))
+		q.Set("direction", direction.Query())
 	}
 	if !sinceUpdatedUtc.IsZero() {
 		q.Set("sinceUpdatedUtc", sinceUpdatedUtc.UTC().Format(time.RFC3339))
@@ -756,9 +1507,7 @@ func (c *Client) GetMatchesUpdates(
 	if minScore > 0 {
 		q.Set("minScore", strconv.FormatFloat(minScore, 'f', -1, 64))
 	}
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
-	}
+	setLimit(q, limit)
 	if minRationaleLength > 0 {
 		q.Set("minRationaleLength", strconv.Itoa(minRationaleLength))
 	}
@@ -777,5 +1526,8 @@ func (c *Client) GetMatchesUpdates(
 	if err := c.doJSON(req, &out); err != nil {
 		return nil, err
 	}
+	if err := c.checkProID("GetMatchesUpdates", proID, out.ProID); err != nil {
+		return nil, err
+	}
 	return &out, nil
 }
@@ -0,0 +1,126 @@
+package manaxclient
+
+import (
+	"slices"
+	"time"
+)
+
+// ClientConfigSnapshot is a redacted view of a Client's effective
+// settings, meant for logging and bug reports. It never contains the pro
+// token, only whether one is set.
+type ClientConfigSnapshot struct {
+	// BaseURL is the API base URL with any user info removed.
+	BaseURL string
+
+	// ProID is the profile id sent as X-Pro-Id, or empty.
+	ProID string
+
+	// HasToken reports whether a pro token is configured.
+	HasToken bool
+
+	// DefaultProIDFromAuth mirrors WithDefaultProIDFromAuth.
+	DefaultProIDFromAuth bool
+
+	// HTTPTimeout is the Timeout of the underlying *http.Client
+	// (0 means none).
+	HTTPTimeout time.Duration
+
+	// CustomHTTPClient reports whether WithHTTPClient (or a non-nil
+	// httpClient in NewClient) replaced http.DefaultClient.
+	CustomHTTPClient bool
+
+	// MinTLSVersion is the WithMinTLSVersion setting, or 0.
+	MinTLSVersion uint16
+
+	// ConnectivityTimeout is the WithConnectivityCheck timeout, or 0.
+	ConnectivityTimeout time.Duration
+
+	// NotFoundAsError and DeleteNotFoundOK mirror the options of the
+	// same name.
+	NotFoundAsError  bool
+	DeleteNotFoundOK bool
+
+	// SupportedSampleRates lists the rates set with
+	// WithSupportedSampleRates; nil means any rate is sent as-is.
+	SupportedSampleRates []int
+
+	// SpeechStatusCacheSize and SpeechStatusCacheTTL describe the
+	// WithSpeechStatusCache cache; both are 0 when it is disabled.
+	SpeechStatusCacheSize int
+	SpeechStatusCacheTTL  time.Duration
+
+	// MaxResponseBytes is the WithMaxResponseBytes limit, or 0.
+	MaxResponseBytes int64
+
+	// MaxConcurrentUploads is the WithMaxConcurrentUploads limit, or 0.
+	MaxConcurrentUploads int
+
+	// RetryMaxAttempts is the WithRetry RetryConfig.MaxAttempts, or 0
+	// when retries are disabled.
+	RetryMaxAttempts int
+
+	// MaxRequestDuration is the WithMaxRequestDuration safety net, or 0
+	// when disabled.
+	MaxRequestDuration time.Duration
+
+	// ProfileMismatchCheck mirrors WithProfileMismatchCheck.
+	ProfileMismatchCheck bool
+
+	// LenientStreamContentType mirrors WithLenientStreamContentType.
+	LenientStreamContentType bool
+
+	// GzipAudioUploads and GzipAudioMinBytes mirror
+	// WithGzipAudioUploads.
+	GzipAudioUploads  bool
+	GzipAudioMinBytes int64
+
+	// HasLogger and HasMetrics report whether WithLogger and WithMetrics
+	// were set.
+	HasLogger  bool
+	HasMetrics bool
+
+	// HasRequestSigner reports whether WithRequestSigner was set.
+	HasRequestSigner bool
+}
+
+// Config returns a snapshot of the client's effective configuration with
+// secrets redacted. The snapshot does not change when the client does.
+func (c *Client) Config() ClientConfigSnapshot {
+	u := *c.baseURL
+	u.User = nil
+	proID, proToken := c.auth()
+
+	cfg := ClientConfigSnapshot{
+		BaseURL:                  u.String(),
+		ProID:                    proID,
+		HasToken:                 proToken != "",
+		DefaultProIDFromAuth:     c.defaultProIDFromAuth,
+		HTTPTimeout:              c.HTTPClient().Timeout,
+		CustomHTTPClient:         c.httpClient != nil && c.ownedTransport == nil,
+		ConnectivityTimeout:      c.connectivityTimeout,
+		NotFoundAsError:          c.notFoundAsError,
+		DeleteNotFoundOK:         c.deleteNotFoundOK,
+		SupportedSampleRates:     slices.Clone(c.supportedSampleRates),
+		MaxResponseBytes:         c.maxResponseBytes,
+		MaxConcurrentUploads:     cap(c.uploadSem),
+		MaxRequestDuration:       c.maxRequestDuration,
+		ProfileMismatchCheck:     c.checkProfileMismatch,
+		LenientStreamContentType: c.lenientStreamContentType,
+		GzipAudioUploads:         c.gzipAudio,
+		GzipAudioMinBytes:        c.gzipAudioMinBytes,
+		HasLogger:                c.logger != nil,
+		HasMetrics:               c.metrics != nil,
+		HasRequestSigner:         c.signer != nil,
+	}
+	if c.ownedTransport != nil && c.ownedTransport.TLSClientConfig != nil {
+		cfg.MinTLSVersion = c.ownedTransport.TLSClientConfig.MinVersion
+	}
+	if c.retry != nil {
+		cfg.RetryMaxAttempts = c.retry.MaxAttempts
+	}
+	if c.statusCache != nil {
+		cfg.SpeechStatusCacheSize = c.statusCache.size
+		cfg.SpeechStatusCacheTTL = c.statusCache.ttl
+	}
+	return cfg
+}
@@ -0,0 +1,62 @@
+package manaxclient
+
+import "time"
+
+// Cursor is the incremental watermark (cursorUpdatedUtc, cursorId) used by
+// the facts and matches snapshot, updates and stream endpoints.
+//
+// Responses return the cursor of the last item they contain; passing it
+// back as sinceUpdatedUtc / sinceId resumes after that item.
+type Cursor struct {
+	// UpdatedUTC is the last seen CursorUpdatedUtc from either a snapshot
+	// or a previous updates chunk.
+	UpdatedUTC time.Time
+
+	// ID is the last seen CursorId associated with UpdatedUTC.
+	ID int64
+}
+
+// After reports whether c is strictly after other in (UpdatedUTC, ID)
+// order: a later timestamp wins, and for equal timestamps the larger ID.
+//
+// Timestamps are compared as UTC instants, ignoring location and any
+// monotonic clock reading, so cursors built from time.Now() compare
+// consistently with cursors decoded from the server.
+func (c Cursor) After(other Cursor) bool {
+	a, b := c.UpdatedUTC.UTC(), other.UpdatedUTC.UTC()
+	if !a.Equal(b) {
+		return a.After(b)
+	}
+	return c.ID > other.ID
+}
+
+// Equal reports whether c and other denote the same position: the same
+// UTC instant and the same ID.
+func (c Cursor) Equal(other Cursor) bool {
+	return c.UpdatedUTC.UTC().Equal(other.UpdatedUTC.UTC()) && c.ID == other.ID
+}
+
+// NextCursorFromFacts returns the largest (UpdatedUTC, ID) cursor among
+// items, in the order used by Cursor.After, whatever the order of the
+// slice. Passing it as sinceUpdatedUtc / sinceId resumes after every
+// item. An empty slice yields the zero Cursor.
+func NextCursorFromFacts(items []FactItem) Cursor {
+	var next Cursor
+	for _, f := range items {
+		if c := (Cursor{UpdatedUTC: f.UpdatedUTC, ID: f.ID}); c.After(next) {
+			next = c
+		}
+	}
+	return next
+}
+
+// NextCursorFromMatches is NextCursorFromFacts for match items.
+func NextCursorFromMatches(items []MatchItem) Cursor {
+	var next Cursor
+	for _, m := range items {
+		if c := (Cursor{UpdatedUTC: m.UpdatedUTC, ID: m.ID}); c.After(next) {
+			next = c
+		}
+	}
+	return next
+}
@@ -0,0 +1,69 @@
+package manaxclient
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCursorOrdering verifies the (UpdatedUTC, ID) ordering of After and
+// Equal, including equal timestamps in different locations.
+func TestCursorOrdering(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	t0Paris := t0.In(time.FixedZone("CET", 3600))
+
+	cases := []struct {
+		name         string
+		a, b         Cursor
+		after, equal bool
+	}{
+		{"same", Cursor{t0, 5}, Cursor{t0, 5}, false, true},
+		{"same instant other zone", Cursor{t0Paris, 5}, Cursor{t0, 5}, false, true},
+		{"same time larger id", Cursor{t0, 6}, Cursor{t0, 5}, true, false},
+		{"same time smaller id", Cursor{t0Paris, 4}, Cursor{t0, 5}, false, false},
+		{"later time smaller id", Cursor{t0.Add(time.Nanosecond), 1}, Cursor{t0, 5}, true, false},
+		{"earlier time larger id", Cursor{t0.Add(-time.Second), 9}, Cursor{t0, 5}, false, false},
+	}
+	for _, tc := range cases {
+		if got := tc.a.After(tc.b); got != tc.after {
+			t.Fatalf("%s: After = %v, want %v", tc.name, got, tc.after)
+		}
+		if got := tc.a.Equal(tc.b); got != tc.equal {
+			t.Fatalf("%s: Equal = %v, want %v", tc.name, got, tc.equal)
+		}
+		if tc.equal && tc.b.After(tc.a) {
+			t.Fatalf("%s: equal cursors must not be After each other", tc.name)
+		}
+	}
+}
+
+// TestNextCursorFromItems verifies that the maximum cursor is found in
+// unsorted slices, that ties on UpdatedUTC are broken by ID, and that an
+// empty slice yields the zero cursor.
+func TestNextCursorFromItems(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	facts := []FactItem{
+		{ID: 9, UpdatedUTC: t0.Add(-time.Minute)},
+		{ID: 3, UpdatedUTC: t0.In(time.FixedZone("CET", 3600))},
+		{ID: 7, UpdatedUTC: t0},
+		{ID: 5, UpdatedUTC: t0},
+	}
+	if got := NextCursorFromFacts(facts); !got.Equal(Cursor{t0, 7}) {
+		t.Fatalf("expected cursor (t0, 7), got %+v", got)
+	}
+
+	matches := []MatchItem{
+		{ID: 100, UpdatedUTC: t0},
+		{ID: 1, UpdatedUTC: t0.Add(time.Nanosecond)},
+	}
+	if got := NextCursorFromMatches(matches); !got.Equal(Cursor{t0.Add(time.Nanosecond), 1}) {
+		t.Fatalf("expected the later timestamp to win, got %+v", got)
+	}
+
+	if got := NextCursorFromFacts(nil); got != (Cursor{}) {
+		t.Fatalf("expected zero cursor for no facts, got %+v", got)
+	}
+	if got := NextCursorFromMatches([]MatchItem{}); got != (Cursor{}) {
+		t.Fatalf("expected zero cursor for no matches, got %+v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package manaxclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors returned by the client. Use errors.Is to test for them,
+// since they are usually wrapped with additional context.
+var (
+	// ErrNotStreaming is returned by the streaming methods when
+	// StreamOptions.RejectNonStreaming is set and the server answered
+	// the SSE request with a fully-buffered body (Content-Length, or
+	// HTTP/1.0-style close-delimited without chunked encoding).
+	ErrNotStreaming = errors.New("manaxclient: SSE response is not streaming")
+
+	// ErrStreamTooLarge is returned by the streaming methods when the
+	// cumulative (decompressed) size of the stream exceeds
+	// StreamOptions.MaxStreamBytes.
+	ErrStreamTooLarge = errors.New("manaxclient: SSE stream exceeds the configured size limit")
+
+	// ErrStreamIdle is returned by the streaming methods when nothing was
+	// received for StreamOptions.IdleTimeout.
+	ErrStreamIdle = errors.New("manaxclient: SSE stream idle timeout")
+
+	// ErrHandlerPanic is returned by the streaming methods when the
+	// handler panicked and StreamOptions.RecoverHandlerPanics is set.
+	ErrHandlerPanic = errors.New("manaxclient: stream handler panicked")
+
+	// ErrHandlerTooSlow is returned by the streaming methods when a
+	// handler call exceeded StreamOptions.HandlerMaxDuration.
+	ErrHandlerTooSlow = errors.New("manaxclient: stream handler too slow")
+
+	// ErrNotFound is returned by lookup methods (GetSpeechStatusByID,
+	// GetSpeechStatusByKey) for HTTP 404 responses when the client was
+	// built with WithNotFoundAsError. The underlying *APIError remains
+	// available via errors.As.
+	ErrNotFound = errors.New("manaxclient: not found")
+
+	// ErrChunkIndexGap is returned by SpeechSession.AddChunkAt when
+	// SpeechSessionOptions.VerifyContiguous is set and the explicit index
+	// would skip one or more chunks.
+	ErrChunkIndexGap = errors.New("manaxclient: chunk index is not contiguous")
+
+	// ErrSessionCancelled is returned by SpeechSession uploads aborted or
+	// refused because SpeechSession.Cancel was called.
+	ErrSessionCancelled = errors.New("manaxclient: speech session cancelled")
+
+	// ErrRetryBudgetExhausted is returned when a retry, reconnection or
+	// refresh would exceed the budget set with WithRetryBudget.
+	ErrRetryBudgetExhausted = errors.New("manaxclient: retry budget exhausted")
+
+	// ErrConflict is returned by conditional updates
+	// (PatchFactReviewStatusWithRequest with ExpectedUpdatedUTC) when the
+	// server rejects the change because the resource was modified
+	// concurrently (HTTP 409 or 412). The underlying *APIError remains
+	// available via errors.As.
+	ErrConflict = errors.New("manaxclient: conflicting concurrent update")
+
+	// ErrResponseTooLarge is returned when a successful response body
+	// exceeds the limit set with WithMaxResponseBytes.
+	ErrResponseTooLarge = errors.New("manaxclient: response body exceeds the configured size limit")
+
+	// ErrMaintenance matches *APIError values for 503 responses the
+	// server flagged as planned maintenance (see APIError.Maintenance).
+	// APIError.RetryAfter holds the announced duration, if any; callers
+	// typically back off longer than for an overload 503.
+	ErrMaintenance = errors.New("manaxclient: server is under maintenance")
+
+	// ErrBodyNotReplayable is returned when a request has to be sent
+	// again (retry) but its body was a one-shot reader without a way to
+	// recreate it.
+	ErrBodyNotReplayable = errors.New("manaxclient: request body cannot be replayed")
+
+	// ErrProfileMismatch is returned, with WithProfileMismatchCheck, when
+	// a response carries a proId different from the requested one.
+	ErrProfileMismatch = errors.New("manaxclient: response proId does not match the request")
+
+	// ErrTokenInvalid is returned by WithVerifiedAuth when the server
+	// reports the (proId, token) pair as not valid.
+	ErrTokenInvalid = errors.New("manaxclient: pro token is not valid")
+
+	// ErrRequestSigning is returned when the WithRequestSigner signer
+	// fails; the request is not sent.
+	ErrRequestSigning = errors.New("manaxclient: request signing failed")
+
+	// ErrUnexpectedContentType is returned by the streaming methods when
+	// a 2xx response is not text/event-stream, e.g. a JSON or HTML page
+	// served by a proxy, unless WithLenientStreamContentType is set.
+	ErrUnexpectedContentType = errors.New("manaxclient: SSE response has an unexpected content type")
+)
+
+// TransportError is returned when a request failed before any HTTP
+// response was received: DNS, connection, TLS or timeout errors from the
+// underlying *http.Client. The cause remains available via errors.Is and
+// errors.As (for example *url.Error or context.DeadlineExceeded).
+type TransportError struct {
+	// Err is the error returned by the *http.Client.
+	Err error
+
+	// Elapsed is the time from sending the request until it failed.
+	Elapsed time.Duration
+}
+
+// Error implements the error interface.
+func (e *TransportError) Error() string {
+	return "http request failed: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying transport error.
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// checkProID returns an error matching ErrProfileMismatch when the client
+// was built with WithProfileMismatchCheck and got, the proId echoed by a
+// response, differs from requested. A response without proId passes.
+func (c *Client) checkProID(op, requested, got string) error {
+	if !c.checkProfileMismatch || got == "" || got == requested {
+		return nil
+	}
+	return fmt.Errorf("%s: %w: requested %q, got %q", op, ErrProfileMismatch, requested, got)
+}
+
+// mapNotFound converts a 404 *APIError into an error matching ErrNotFound
+// when the client is configured with WithNotFoundAsError. Other errors are
+// returned unchanged.
+func (c *Client) mapNotFound(op string, err error) error {
+	if !c.notFoundAsError {
+		return err
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w: %w", op, ErrNotFound, apiErr)
+	}
+	return err
+}
+
+// mapConflict converts a 409/412 *APIError into an error matching
+// ErrConflict. Other errors are returned unchanged.
+func mapConflict(op string, err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusPreconditionFailed) {
+		return fmt.Errorf("%s: %w: %w", op, ErrConflict, apiErr)
+	}
+	return err
+}
@@ -0,0 +1,236 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithNotFoundAsError verifies that a 404 from a lookup method is
+// mapped to ErrNotFound with a nil result, while the APIError stays
+// reachable via errors.As.
+func TestWithNotFoundAsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"no such row"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClientWithOptions(srv.URL, WithNotFoundAsError())
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+
+	resp, err := client.GetSpeechStatusByID(context.Background(), 7)
+	if resp != nil {
+		t.Fatalf("expected nil response, got %#v", resp)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected wrapped *APIError with 404, got %v", err)
+	}
+
+	if _, err := client.GetSpeechStatusByKey(context.Background(), "p_123", "s_1", 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from GetSpeechStatusByKey, got %v", err)
+	}
+}
+
+// TestNotFoundDefault ensures that without the option a 404 surfaces as a
+// plain *APIError that does not match ErrNotFound.
+func TestNotFoundDefault(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	_, err := client.GetSpeechStatusByID(context.Background(), 7)
+	if errors.Is(err, ErrNotFound) {
+		t.Fatalf("did not expect ErrNotFound without the option")
+	}
+	if _, ok := err.(*APIError); !ok {
+		t.Fatalf("expected *APIError, got %T (%v)", err, err)
+	}
+}
+
+// TestErrMaintenance verifies that a 503 flagged by header or body matches
+// ErrMaintenance with its Retry-After, while a plain overload 503 does not.
+func TestErrMaintenance(t *testing.T) {
+	cases := []struct {
+		name        string
+		header      map[string]string
+		body        string
+		maintenance bool
+		retryAfter  time.Duration
+	}{
+		{"header", map[string]string{"X-Maintenance": "true", "Retry-After": "600"}, `{"error":"down"}`, true, 10 * time.Minute},
+		{"body", nil, `{"error":"planned","maintenance":true}`, true, 0},
+		{"overload", map[string]string{"Retry-After": "5"}, `{"error":"busy"}`, false, 5 * time.Second},
+		{"false flag", map[string]string{"X-Maintenance": "false"}, ``, false, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			client, _ := NewClient(srv.URL, nil)
+			_, err := client.GetFactsUpdates(context.Background(), "p_123", time.Time{}, 0, 0)
+
+			if errors.Is(err, ErrMaintenance) != tc.maintenance {
+				t.Fatalf("expected maintenance=%v, got %v", tc.maintenance, err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("expected 503 APIError, got %v", err)
+			}
+			if apiErr.RetryAfter != tc.retryAfter {
+				t.Fatalf("expected RetryAfter %s, got %s", tc.retryAfter, apiErr.RetryAfter)
+			}
+		})
+	}
+}
+
+// TestParseRetryAfter verifies delay-seconds and HTTP-date forms.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for v, want := range map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"-1":                            0,
+		"soon":                          0,
+		"Wed, 01 Jan 2025 12:01:30 GMT": 90 * time.Second,
+		"Wed, 01 Jan 2025 11:00:00 GMT": 0,
+	} {
+		h := http.Header{}
+		h.Set("Retry-After", v)
+		if got := parseRetryAfter(h, now); got != want {
+			t.Fatalf("Retry-After %q: expected %s, got %s", v, want, got)
+		}
+	}
+}
+
+// TestErrorElapsed verifies that APIError and TransportError report how
+// long the failed request took.
+func TestErrorElapsed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	_, err = c.GetSpeechStatusByID(context.Background(), 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Elapsed < 20*time.Millisecond {
+		t.Fatalf("expected Elapsed >= 20ms, got %v", apiErr.Elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetSpeechStatusByID(ctx, 1)
+	var tErr *TransportError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("expected *TransportError, got %v", err)
+	}
+	if tErr.Elapsed <= 0 {
+		t.Fatalf("expected positive Elapsed, got %v", tErr.Elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the cause to stay reachable, got %v", err)
+	}
+}
+
+// TestWithProfileMismatchCheck verifies that a response echoing another
+// proId fails with ErrProfileMismatch only when the check is enabled, for
+// plain calls and streams alike.
+func TestWithProfileMismatchCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/facts/items/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_other"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"proId":"p_other","items":[]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := c.GetFactsSnapshot(ctx, "p_123", 0); err != nil {
+		t.Fatalf("expected no check by default, got %v", err)
+	}
+
+	c = newClientWithOptions(t, srv.URL, WithProfileMismatchCheck())
+	if _, err := c.GetFactsSnapshot(ctx, "p_123", 0); !errors.Is(err, ErrProfileMismatch) {
+		t.Fatalf("expected ErrProfileMismatch from GetFactsSnapshot, got %v", err)
+	}
+	if _, err := c.GetFactsUpdates(ctx, "p_other", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("expected matching proId to pass, got %v", err)
+	}
+
+	called := false
+	err = c.StreamFacts(ctx, "p_123", func(ctx context.Context, chunk *FactsStreamChunk) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrProfileMismatch) || called {
+		t.Fatalf("expected the stream to stop before the handler, got %v (called=%v)", err, called)
+	}
+}
+
+// TestAPIError_Details verifies that a "details" array is captured next to
+// the top-level message, and that bodies without it leave Details nil.
+func TestAPIError_Details(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"validation failed","details":["sessionId is required","chunkIndex must be >= 0",{"field":"text"}]}`))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	_, err := client.GetSpeechStatusByKey(context.Background(), "p_123", "s_1", 0)
+	var e *APIError
+	if !errors.As(err, &e) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	want := []string{"sessionId is required", "chunkIndex must be >= 0", `{"field":"text"}`}
+	if e.Message != "validation failed" || !reflect.DeepEqual(e.Details, want) {
+		t.Fatalf("unexpected error fields: message=%q details=%q", e.Message, e.Details)
+	}
+	if !strings.Contains(e.Error(), `details=["sessionId is required" "chunkIndex must be >= 0"`) {
+		t.Fatalf("expected details in the error string, got %q", e.Error())
+	}
+
+	resp := &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request", Header: http.Header{}}
+	for _, body := range []string{`{"error":"bad"}`, `{"error":"bad","details":"not a list"}`} {
+		e := newAPIError(resp, []byte(body))
+		if e.Message != "bad" || e.Details != nil {
+			t.Fatalf("%s: unexpected error fields: message=%q details=%q", body, e.Message, e.Details)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// DefaultExportPageSize is the number of facts requested per page by
+// StreamFactsToCSV.
+const DefaultExportPageSize = 500
+
+// factsCSVHeader is the header row written by StreamFactsToCSV.
+var factsCSVHeader = []string{
+	"id", "proId", "factText", "factHash", "status", "falseReason",
+	"createdUtc", "lastSeenUtc", "updatedUtc", "reviewStatus", "reviewUpdatedUtc", "isWritable",
+}
+
+// StreamFactsToCSV exports all facts of proID to w as CSV, one header row
+// followed by one row per fact in (UpdatedUTC, ID) order.
+//
+// Facts are fetched page by page through GetFactsUpdates starting from
+// the zero cursor, and every page is written and flushed to w before the
+// next one is requested, so memory use is bounded by the page size
+// (DefaultExportPageSize) regardless of the number of facts. Times are
+// RFC3339Nano in UTC; absent optional values are empty cells.
+//
+// On error, w holds the rows of the pages exported so far.
+func (c *Client) StreamFactsToCSV(ctx context.Context, w io.Writer, proID string) error {
+	ctx = withOperation(ctx, "StreamFactsToCSV")
+	cw := csv.NewWriter(w)
+	if err := cw.Write(factsCSVHeader); err != nil {
+		return fmt.Errorf("StreamFactsToCSV: write header: %w", err)
+	}
+
+	var cursor Cursor
+	row := make([]string, len(factsCSVHeader))
+	for {
+		page, err := c.GetFactsUpdatesPage(ctx, proID, cursor, DefaultExportPageSize)
+		if err != nil {
+			cw.Flush()
+			return err
+		}
+		if len(page.Items) == 0 {
+			break
+		}
+		for _, f := range page.Items {
+			if err := cw.Write(factCSVRow(row, f)); err != nil {
+				return fmt.Errorf("StreamFactsToCSV: write fact %d: %w", f.ID, err)
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("StreamFactsToCSV: flush: %w", err)
+		}
+		if !page.CursorAdvanced() {
+			return fmt.Errorf("StreamFactsToCSV: cursor did not advance past %d (%s)",
+				cursor.ID, cursor.UpdatedUTC.UTC().Format(time.RFC3339Nano))
+		}
+		cursor = page.Returned()
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("StreamFactsToCSV: flush: %w", err)
+	}
+	return nil
+}
+
+// factCSVRow fills row with the CSV cells of f and returns it.
+func factCSVRow(row []string, f FactItem) []string {
+	row[0] = strconv.FormatInt(f.ID, 10)
+	row[1] = f.ProID
+	row[2] = f.FactText
+	row[3] = f.FactHash
+	row[4] = f.Status
+	row[5] = derefString(f.FalseReason)
+	row[6] = formatCSVTime(f.CreatedUTC)
+	row[7] = formatCSVTime(f.LastSeenUTC)
+	row[8] = formatCSVTime(f.UpdatedUTC)
+	row[9] = derefString(f.ReviewStatus)
+	row[10] = ""
+	if f.ReviewUpdatedUTC != nil {
+		row[10] = formatCSVTime(*f.ReviewUpdatedUTC)
+	}
+	row[11] = strconv.FormatBool(f.IsWritable)
+	return row
+}
+
+// formatCSVTime formats t as RFC3339Nano in UTC, or "" for the zero time.
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// derefString returns *s, or "" when s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
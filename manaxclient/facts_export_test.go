@@ -0,0 +1,88 @@
+package manaxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for use by the test handler and the
+// exporter at the same time.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) lines() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Count(b.buf.String(), "\n")
+}
+
+// TestStreamFactsToCSV verifies that facts are exported across several
+// pages and that each page is written before the next one is requested.
+func TestStreamFactsToCSV(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	const total, perPage = 7, 3
+	out := &lockedBuffer{}
+	var linesAtRequest []int
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		linesAtRequest = append(linesAtRequest, out.lines())
+		since, _ := strconv.ParseInt(r.URL.Query().Get("sinceId"), 10, 64)
+		resp := FactsUpdatesResponse{Items: []FactItem{}}
+		for id := since + 1; id <= total && id <= since+perPage; id++ {
+			resp.Items = append(resp.Items, FactItem{
+				ID: id, ProID: "p_123", FactText: "fact, " + strconv.FormatInt(id, 10),
+				Status: "ok", UpdatedUTC: t0.Add(time.Duration(id) * time.Second),
+			})
+			resp.CursorUpdatedUTC = t0.Add(time.Duration(id) * time.Second)
+			resp.CursorID = id
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	if err := client.StreamFactsToCSV(context.Background(), out, "p_123"); err != nil {
+		t.Fatalf("StreamFactsToCSV returned error: %v", err)
+	}
+
+	// Requests for pages 1-3 and the final empty page; the header is
+	// written up front.
+	want := []int{0, 1 + perPage, 1 + 2*perPage, 1 + total}
+	if len(linesAtRequest) != len(want) {
+		t.Fatalf("expected %d requests, got %v", len(want), linesAtRequest)
+	}
+	for i := 1; i < len(want); i++ {
+		if linesAtRequest[i] != want[i] {
+			t.Fatalf("expected %v lines written before each request, got %v", want, linesAtRequest)
+		}
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out.buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 1+total || records[0][0] != "id" {
+		t.Fatalf("unexpected records: %q", records)
+	}
+	if r := records[7]; r[0] != "7" || r[2] != "fact, 7" || r[8] != "2025-01-01T00:00:07Z" || r[5] != "" || r[11] != "false" {
+		t.Fatalf("unexpected last row: %q", r)
+	}
+}
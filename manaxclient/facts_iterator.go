@@ -0,0 +1,118 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FactsIterator walks the facts of a profile one at a time: it starts
+// with the snapshot, then pages through GetFactsUpdates from the snapshot
+// cursor, refetching whenever the current page is exhausted, until the
+// updates are empty.
+//
+// The cursor of the last fact returned (see Cursor) can be persisted and
+// passed to NewFactsIterator to resume after a restart without fetching
+// the snapshot again. A FactsIterator is not safe for concurrent use.
+type FactsIterator struct {
+	client *Client
+	proID  string
+
+	// PageLimit is the limit of every snapshot and updates request; 0
+	// lets the server choose, LimitAll requests its maximum. Set it
+	// before the first call to Next.
+	PageLimit int
+
+	cursor  Cursor
+	started bool
+	page    []FactItem
+	pageEnd Cursor
+	pos     int
+}
+
+// NewFactsIterator returns a FactsIterator over the facts of proID. With
+// a zero since it starts with the snapshot; otherwise it resumes with the
+// updates after since, typically a Cursor persisted earlier. Nothing is
+// fetched until Next is called.
+func (c *Client) NewFactsIterator(proID string, since Cursor) (*FactsIterator, error) {
+	proID = c.resolveProID(context.Background(), proID)
+	if proID == "" {
+		return nil, errors.New("NewFactsIterator: proID must not be empty")
+	}
+	return &FactsIterator{
+		client:  c,
+		proID:   proID,
+		cursor:  since,
+		started: !since.UpdatedUTC.IsZero() || since.ID != 0,
+	}, nil
+}
+
+// Next returns the next fact. ok is false with a nil error once the
+// iterator has caught up, i.e. an updates request returned no items;
+// calling Next again later polls for newer updates. Facts are returned in
+// (UpdatedUTC, ID) order within each page.
+//
+// A failed request returns its error and leaves the iterator unchanged,
+// so Next can be retried.
+func (it *FactsIterator) Next(ctx context.Context) (item *FactItem, ok bool, err error) {
+	if it.pos >= len(it.page) {
+		if err := it.fetch(withOperation(ctx, "FactsIterator")); err != nil {
+			return nil, false, err
+		}
+		if len(it.page) == 0 {
+			return nil, false, nil
+		}
+	}
+
+	f := it.page[it.pos]
+	it.pos++
+	it.cursor = Cursor{UpdatedUTC: f.UpdatedUTC, ID: f.ID}
+	if it.pos == len(it.page) && it.pageEnd.After(it.cursor) {
+		it.cursor = it.pageEnd
+	}
+	return &f, true, nil
+}
+
+// Cursor returns the cursor of the last fact returned by Next (or the
+// cursor the iterator was created with). Resuming from it with
+// NewFactsIterator continues right after that fact.
+func (it *FactsIterator) Cursor() Cursor {
+	return it.cursor
+}
+
+// fetch loads the next page: the snapshot on the first call, updates
+// after the cursor afterwards. Pages are sorted so that a cursor taken
+// in the middle of one never skips a fact.
+func (it *FactsIterator) fetch(ctx context.Context) error {
+	var (
+		items []FactItem
+		end   Cursor
+	)
+	if !it.started {
+		snap, err := it.client.getFactsSnapshot(ctx, "FactsIterator", FactsSnapshotRequest{ProID: it.proID, Limit: it.PageLimit})
+		if err != nil {
+			return err
+		}
+		items = append([]FactItem(nil), snap.Items...)
+		sortFactItems(items)
+		end = Cursor{UpdatedUTC: snap.CursorUpdatedUTC, ID: snap.CursorID}
+	} else {
+		upd, err := it.client.GetFactsUpdates(ctx, it.proID, it.cursor.UpdatedUTC, it.cursor.ID, it.PageLimit)
+		if err != nil {
+			return err
+		}
+		end = Cursor{UpdatedUTC: upd.CursorUpdatedUTC, ID: upd.CursorID}
+		if len(upd.Items) > 0 && !end.After(it.cursor) {
+			return fmt.Errorf("FactsIterator: updates cursor did not advance past %v", it.cursor)
+		}
+		items = upd.Items
+		sortFactItems(items)
+	}
+
+	it.started = true
+	it.page, it.pos, it.pageEnd = items, 0, end
+	if len(items) == 0 && end.After(it.cursor) {
+		it.cursor = end
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestFactsIterator verifies that the iterator yields the snapshot and
+// then the update pages in order, even when the server does not sort
+// them, stops when caught up, and that its cursor resumes a new iterator
+// right after the last fact returned.
+func TestFactsIterator(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	facts := make([]FactItem, 7)
+	for i := range facts {
+		facts[i] = FactItem{ID: int64(i + 1), ProID: "p_123", UpdatedUTC: base.Add(time.Duration(i) * time.Minute)}
+	}
+
+	var requests []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		requests = append(requests, r.URL.Path+"?"+q.Get("sinceId"))
+		if q.Get("limit") != "3" {
+			t.Errorf("expected limit=3, got %q", q.Get("limit"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/facts/items/snapshot" {
+			// The snapshot is not necessarily sorted.
+			_ = json.NewEncoder(w).Encode(FactsItemsResponse{ProID: "p_123", CursorUpdatedUTC: facts[2].UpdatedUTC, CursorID: 3,
+				Items: []FactItem{facts[2], facts[0], facts[1]}})
+			return
+		}
+		since, _ := strconv.ParseInt(q.Get("sinceId"), 10, 64)
+		upd := FactsUpdatesResponse{ProID: "p_123"}
+		for _, f := range facts {
+			if f.ID > since && len(upd.Items) < 3 {
+				upd.Items = append(upd.Items, f)
+				upd.CursorUpdatedUTC, upd.CursorID = f.UpdatedUTC, f.ID
+			}
+		}
+		// Update pages are not necessarily sorted either.
+		slices.Reverse(upd.Items)
+		_ = json.NewEncoder(w).Encode(upd)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+	ctx := context.Background()
+
+	it, err := client.NewFactsIterator("p_123", Cursor{})
+	if err != nil {
+		t.Fatalf("NewFactsIterator failed: %v", err)
+	}
+	it.PageLimit = 3
+
+	var ids []int64
+	var resumeAt Cursor
+	for {
+		f, ok, err := it.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if !ok {
+			break
+		}
+		ids = append(ids, f.ID)
+		if f.ID == 4 {
+			resumeAt = it.Cursor()
+		}
+	}
+	if !slices.Equal(ids, []int64{1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf("expected facts 1..7 in order, got %v", ids)
+	}
+	want := []string{"/api/facts/items/snapshot?", "/api/facts/items/updates?3", "/api/facts/items/updates?6", "/api/facts/items/updates?7"}
+	if len(requests) != len(want) {
+		t.Fatalf("unexpected requests: %q", requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Fatalf("unexpected requests: %q", requests)
+		}
+	}
+	if it.Cursor().ID != 7 {
+		t.Fatalf("expected the cursor at fact 7, got %+v", it.Cursor())
+	}
+
+	// Resume from a cursor persisted in the middle of a page.
+	requests = nil
+	it, err = client.NewFactsIterator("p_123", resumeAt)
+	if err != nil {
+		t.Fatalf("NewFactsIterator failed: %v", err)
+	}
+	it.PageLimit = 3
+	f, ok, err := it.Next(ctx)
+	if err != nil || !ok || f.ID != 5 {
+		t.Fatalf("expected to resume at fact 5, got %+v, %v, %v", f, ok, err)
+	}
+	if requests[0] != "/api/facts/items/updates?4" {
+		t.Fatalf("expected resumption without snapshot, got %q", requests)
+	}
+}
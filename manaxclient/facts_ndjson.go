@@ -0,0 +1,58 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// FactsNDJSONReader streams the facts of proID as NDJSON: it runs
+// StreamFacts in the background and writes every FactItem of every chunk
+// (initial snapshot and updates alike) as one JSON object followed by a
+// newline. The output can be piped to other processes line by line.
+//
+// The stream advances only as fast as the reader consumes it; nothing is
+// buffered beyond the line being read. Read returns io.EOF once the
+// server ends the stream, and the stream error otherwise, including
+// ctx.Err() after cancellation. Errors in the arguments are reported the
+// same way by the first Read.
+//
+// Close stops the stream and waits for it to end; it must be called when
+// the reader is no longer needed.
+func (c *Client) FactsNDJSONReader(ctx context.Context, proID string) io.ReadCloser {
+	ctx = withOperation(ctx, "FactsNDJSONReader")
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	r := &factsNDJSONReader{PipeReader: pr, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(r.done)
+		enc := json.NewEncoder(pw)
+		err := c.streamFacts(ctx, "FactsNDJSONReader", proID, FactsStreamOptions{},
+			func(ctx context.Context, chunk *FactsStreamChunk) error {
+				for i := range chunk.Items {
+					if err := enc.Encode(&chunk.Items[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		pw.CloseWithError(err)
+	}()
+	return r
+}
+
+// factsNDJSONReader is the io.ReadCloser returned by FactsNDJSONReader.
+type factsNDJSONReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close stops the underlying stream and waits for it to return.
+func (r *factsNDJSONReader) Close() error {
+	r.cancel()
+	err := r.PipeReader.Close()
+	<-r.done
+	return err
+}
@@ -0,0 +1,87 @@
+package manaxclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestFactsNDJSONReader verifies that every streamed fact becomes one
+// JSON line and that the reader ends with io.EOF with the stream.
+func TestFactsNDJSONReader(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", CursorID: 2, Items: []FactItem{
+			{ID: 1, ProID: "p_123", FactText: "one"},
+			{ID: 2, ProID: "p_123", FactText: "two\nlines"},
+		}})
+		_, _ = w.Write([]byte(": ping\n\n"))
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", CursorID: 3, Items: []FactItem{
+			{ID: 3, ProID: "p_123", FactText: "three"},
+		}})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	r := client.FactsNDJSONReader(context.Background(), "p_123")
+	defer r.Close()
+
+	var ids []int64
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var item FactItem
+		if err := json.Unmarshal(sc.Bytes(), &item); err != nil {
+			t.Fatalf("line %q is not a FactItem: %v", sc.Text(), err)
+		}
+		ids = append(ids, item.ID)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("expected clean EOF, got %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Fatalf("expected facts 1, 2, 3, got %v", ids)
+	}
+}
+
+// TestFactsNDJSONReader_Cancel verifies that cancelling the context ends
+// an open stream with the context error and that Close returns promptly.
+func TestFactsNDJSONReader_Cancel(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", Items: []FactItem{{ID: 1}}})
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := client.FactsNDJSONReader(ctx, "p_123")
+
+	br := bufio.NewReader(r)
+	if _, err := br.ReadBytes('\n'); err != nil {
+		t.Fatalf("expected a first line, got %v", err)
+	}
+	cancel()
+	if _, err := io.ReadAll(br); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Close did not return")
+	}
+}
@@ -0,0 +1,41 @@
+package manaxclient
+
+import "context"
+
+// FactsUpdatesPage is a FactsUpdatesResponse together with the cursor it
+// was requested with, so that pagination progress can be checked.
+type FactsUpdatesPage struct {
+	*FactsUpdatesResponse
+
+	// Requested is the cursor passed as sinceUpdatedUtc / sinceId.
+	Requested Cursor
+}
+
+// Returned is the cursor reported by the server in the response.
+func (p *FactsUpdatesPage) Returned() Cursor {
+	return Cursor{UpdatedUTC: p.CursorUpdatedUTC, ID: p.CursorID}
+}
+
+// CursorAdvanced reports whether the returned cursor is strictly after
+// the requested one. A page whose cursor did not move cannot be used to
+// make progress: requesting it again yields the same page. Empty pages
+// normally do not advance; a non-empty page that does not advance
+// points to a server-side pagination bug.
+func (p *FactsUpdatesPage) CursorAdvanced() bool {
+	return p.Returned().After(p.Requested)
+}
+
+// GetFactsUpdatesPage calls GetFactsUpdates with since and returns the
+// response wrapped with the requested cursor.
+func (c *Client) GetFactsUpdatesPage(
+	ctx context.Context,
+	proID string,
+	since Cursor,
+	limit int,
+) (*FactsUpdatesPage, error) {
+	upd, err := c.GetFactsUpdates(ctx, proID, since.UpdatedUTC, since.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &FactsUpdatesPage{FactsUpdatesResponse: upd, Requested: since}, nil
+}
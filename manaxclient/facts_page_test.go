@@ -0,0 +1,43 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestGetFactsUpdatesPage_CursorAdvanced verifies that CursorAdvanced
+// reports progress only when the server returns a later cursor.
+func TestGetFactsUpdatesPage_CursorAdvanced(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		resp := FactsUpdatesResponse{ProID: "p_123", CursorUpdatedUTC: t0, CursorID: 10}
+		if r.URL.Query().Get("sinceId") == "3" {
+			resp.CursorID = 11
+			resp.Items = []FactItem{{ID: 11}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	page, err := client.GetFactsUpdatesPage(context.Background(), "p_123", Cursor{UpdatedUTC: t0, ID: 3}, 0)
+	if err != nil {
+		t.Fatalf("GetFactsUpdatesPage returned error: %v", err)
+	}
+	if !page.CursorAdvanced() || page.Returned().ID != 11 || page.Requested.ID != 3 || len(page.Items) != 1 {
+		t.Fatalf("expected advanced page, got %#v", page)
+	}
+
+	stuck, err := client.GetFactsUpdatesPage(context.Background(), "p_123", Cursor{UpdatedUTC: t0, ID: 10}, 0)
+	if err != nil {
+		t.Fatalf("GetFactsUpdatesPage returned error: %v", err)
+	}
+	if stuck.CursorAdvanced() {
+		t.Fatalf("expected CursorAdvanced=false when the cursor did not move, got %#v", stuck.Returned())
+	}
+}
@@ -3,12 +3,8 @@ package manaxclient
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
-	"time"
 )
 
 // FactsStreamChunk represents a single "facts" SSE event payload.
@@ -35,6 +31,58 @@ type FactsStreamChunk = FactsItemsResponse
 // is propagated back to the caller of StreamFacts.
 type FactsStreamHandler func(ctx context.Context, chunk *FactsStreamChunk) error
 
+// snapshotChunkKey is the context key marking the handler call of the
+// first chunk of a facts stream connection (see IsSnapshotChunk).
+type snapshotChunkKey struct{}
+
+// IsSnapshotChunk reports whether the chunk passed to a FactsStreamHandler
+// together with ctx is the server's initial snapshot: the first chunk
+// received on a connection, which holds the full window of facts rather
+// than an increment. With FactsStreamOptions.Reconnect every connection
+// starts with such a snapshot, and state built from earlier chunks should
+// be reset rather than merged (see FactsView.Apply).
+//
+// It is false for every later chunk, for all chunks when
+// FactsStreamOptions.SnapshotLimit truncates the snapshot, and for
+// contexts not passed to a facts stream handler.
+func IsSnapshotChunk(ctx context.Context) bool {
+	snapshot, _ := ctx.Value(snapshotChunkKey{}).(bool)
+	return snapshot
+}
+
+// FactsStreamOptions configures StreamFactsWithOptions. The zero value
+// is equivalent to calling StreamFacts.
+type FactsStreamOptions struct {
+	// StreamOptions carries behaviour shared with the other streams.
+	StreamOptions
+
+	// SnapshotLimit bounds the initial snapshot sent on connect, passed
+	// as limit=...; 0 (or LimitAll, sent explicitly) keeps the server's
+	// full window. Subsequent updates are not restricted.
+	//
+	// The server must honor the parameter: one that ignores it still
+	// sends its full snapshot, and the client does not truncate it.
+	SnapshotLimit int
+
+	// EventNames lists the SSE event names decoded as facts chunks.
+	// Events without an "event:" field are treated as EventNames[0].
+	// If empty, only "facts" is accepted.
+	EventNames []string
+
+	// Reconnect, when non-nil, makes StreamFactsWithOptions reopen the
+	// stream with exponential backoff (or after the server's "retry:"
+	// delay, if it sent one) after the server closes it or a transient
+	// error occurs, until ctx is cancelled, the handler fails or the
+	// policy gives up. Each reconnection consumes one unit of the ctx
+	// retry budget, if any (see WithRetryBudget).
+	//
+	// The facts stream has no resume cursor: after every reconnection
+	// the server sends its initial snapshot again, so handlers must
+	// tolerate chunks they have already seen. IsSnapshotChunk tells such
+	// snapshots apart from updates.
+	Reconnect *ReconnectPolicy
+}
+
 // StreamFacts establishes an SSE connection to
 //   GET /api/facts/items/stream?proId=...
 //
@@ -66,109 +114,91 @@ func (c *Client) StreamFacts(
 	proID string,
 	handler FactsStreamHandler,
 ) error {
-	proID = strings.TrimSpace(proID)
+	return c.streamFacts(ctx, "StreamFacts", proID, FactsStreamOptions{}, handler)
+}
+
+// StreamFactsWithOptions behaves like StreamFacts but accepts additional
+// stream settings (see FactsStreamOptions): snapshot limit, idle timeout,
+// event names and automatic reconnection.
+func (c *Client) StreamFactsWithOptions(
+	ctx context.Context,
+	proID string,
+	opt FactsStreamOptions,
+	handler FactsStreamHandler,
+) error {
+	return c.streamFacts(ctx, "StreamFactsWithOptions", proID, opt, handler)
+}
+
+// streamFacts implements StreamFacts and StreamFactsWithOptions; op is
+// the public method name used in error messages.
+func (c *Client) streamFacts(
+	ctx context.Context,
+	op string,
+	proID string,
+	opt FactsStreamOptions,
+	handler FactsStreamHandler,
+) error {
+	proID = c.resolveProID(ctx, proID)
 	if proID == "" {
-		return errors.New("StreamFacts: proID must not be empty")
+		return fmt.Errorf("%s: proID must not be empty", op)
 	}
 	if handler == nil {
-		return errors.New("StreamFacts: handler must not be nil")
+		return fmt.Errorf("%s: handler must not be nil", op)
 	}
 
-	// Build query: ?proId=<value>
+	if opt.SnapshotLimit < 0 && opt.SnapshotLimit != LimitAll {
+		return fmt.Errorf("%s: SnapshotLimit must be >= 0 or LimitAll", op)
+	}
+
+	// Build query: ?proId=<value>[&limit=<n>]
 	q := url.Values{}
 	q.Set("proId", proID)
+	setLimit(q, opt.SnapshotLimit)
 
-	// Create HTTP request bound to the provided context.
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/facts/items/stream", q, nil)
-	if err != nil {
-		return fmt.Errorf("StreamFacts: create request: %w", err)
+	names := opt.EventNames
+	if len(names) == 0 {
+		names = []string{"facts"}
 	}
 
-	// SSE best practice: explicitly express preference for text/event-stream.
-	h := http.Header{}
-	h.Set("Accept", "text/event-stream")
-	c.applyHeaders(req, h)
-
-	resp, err := c.HTTPClient().Do(req)
-	if err != nil {
-		// If context has been cancelled, surface context error directly.
-		if ctxErr := ctx.Err(); ctxErr != nil {
-			return ctxErr
-		}
-		return fmt.Errorf("StreamFacts: http request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// Read limited body to avoid unbounded memory usage.
-		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
-		// Try to decode JSON error, if any.
-		var payload struct {
-			Error string `json:"error"`
+	var r streamReconnect
+	decode := func(ctx context.Context, ev *SSEEvent, _ SSEEventMeta) error {
+		var chunk FactsStreamChunk
+		if err := json.Unmarshal(ev.Data, &chunk); err != nil {
+			r.stopErr = fmt.Errorf("%s: decode JSON payload: %w", op, err)
+			return r.stopErr
 		}
-		_ = json.Unmarshal(data, &payload)
-
-		msg := strings.TrimSpace(payload.Error)
-		if msg == "" && len(data) > 0 {
-			msg = strings.TrimSpace(string(data))
+		if err := c.checkProID(op, proID, chunk.ProID); err != nil {
+			r.stopErr = err
+			return err
 		}
-		if msg == "" {
-			msg = resp.Status
+		// A limited snapshot is not the full window and must not be
+		// mistaken for an authoritative one.
+		snapshot := r.handled == 0 && opt.SnapshotLimit <= 0
+		if err := handler(context.WithValue(ctx, snapshotChunkKey{}, snapshot), &chunk); err != nil {
+			r.stopErr = err
+			return err
 		}
-
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    msg,
-			Body:       data,
+		if opt.LastCursor != nil && !chunk.CursorUpdatedUTC.IsZero() {
+			*opt.LastCursor = Cursor{UpdatedUTC: chunk.CursorUpdatedUTC, ID: chunk.CursorID}
 		}
+		r.handled++
+		return nil
+	}
+	handlers := make(map[string]EventHandler, len(names))
+	for _, name := range names {
+		handlers[name] = decode
 	}
 
-	reader := newSSEReader(resp.Body)
-
-	for {
-		ev, err := reader.ReadEvent()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				// Normal termination: server closed the stream.
-				// If the caller wants automatic reconnection, they
-				// can implement it around this method.
-				return nil
-			}
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				// Prefer propagating context cancellation error when
-				// both a read error and a cancelled context exist.
-				return ctxErr
-			}
-			return fmt.Errorf("StreamFacts: read SSE event: %w", err)
-		}
-		if ev == nil {
-			continue
-		}
-
-		// Ignore pure comment events (keepalives, "ping", etc.).
-		if ev.Comment != "" && ev.Event == "" && len(ev.Data) == 0 {
-			continue
-		}
-
-		// Only process "facts" events; ignore any other event types
-		// to keep the stream forwards-compatible.
-		if ev.Event != "" && ev.Event != "facts" {
-			continue
-		}
-
-		if len(ev.Data) == 0 {
-			// Malformed event: event type without data.
-			// Treat as error to avoid silently hiding server bugs.
-			return fmt.Errorf("StreamFacts: received event \"facts\" with empty data payload")
-		}
-
-		var chunk FactsStreamChunk
-		if err := json.Unmarshal(ev.Data, &chunk); err != nil {
-			return fmt.Errorf("StreamFacts: decode JSON payload: %w", err)
-		}
-
-		if err := handler(ctx, &chunk); err != nil {
-			return err
-		}
+	s := sseStream{
+		op:           op,
+		endpoint:     "/api/facts/items/stream",
+		query:        q,
+		handlers:     handlers,
+		defaultEvent: names[0],
+		opt:          opt.StreamOptions,
+	}
+	if opt.Reconnect == nil {
+		return c.runStream(ctx, s)
 	}
-}
\ No newline at end of file
+	return c.runStreamReconnecting(ctx, s, *opt.Reconnect, &r)
+}
@@ -3,6 +3,7 @@ package manaxclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -101,4 +102,232 @@ func TestStreamFacts_Basic(t *testing.T) {
 	if got[0].Items[0].FactText != "one" || got[1].Items[0].FactText != "two" {
 		t.Fatalf("unexpected chunks: %#v", got)
 	}
-}
\ No newline at end of file
+}
+
+// TestStreamFactsWithOptions_SnapshotLimit verifies that SnapshotLimit is
+// sent as limit=... on the stream request and omitted when zero.
+func TestStreamFactsWithOptions_SnapshotLimit(t *testing.T) {
+	var gotLimit []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotLimit = append(gotLimit, r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "text/event-stream")
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	noop := func(ctx context.Context, chunk *FactsStreamChunk) error { return nil }
+	if err := client.StreamFactsWithOptions(context.Background(), "p_123", FactsStreamOptions{SnapshotLimit: 50}, noop); err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if err := client.StreamFacts(context.Background(), "p_123", noop); err != nil {
+		t.Fatalf("StreamFacts returned error: %v", err)
+	}
+	if len(gotLimit) != 2 || gotLimit[0] != "50" || gotLimit[1] != "" {
+		t.Fatalf("unexpected limit params: %q", gotLimit)
+	}
+}
+
+// TestStreamFactsWithOptions_EventNamesAndReconnect verifies that custom
+// event names are decoded and that the stream reconnects after the
+// server closes it, until MaxEvents is reached.
+func TestStreamFactsWithOptions_EventNamesAndReconnect(t *testing.T) {
+	conns := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conns++
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: int64(conns * 10)})
+		writeSSEEvent(t, w, "fact-update", FactsStreamChunk{CursorID: int64(conns*10 + 1)})
+		// Returning closes the connection.
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var got []int64
+	opt := FactsStreamOptions{
+		EventNames: []string{"fact-update"},
+		Reconnect:  &ReconnectPolicy{InitialBackoff: time.Millisecond},
+	}
+	opt.MaxEvents = 1
+	collect := func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first connection delivers one event; MaxEvents ends the call.
+	if err := client.StreamFactsWithOptions(ctx, "p_123", opt, collect); err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if len(got) != 1 || got[0] != 11 || conns != 1 {
+		t.Fatalf("expected only the fact-update event of one connection, got %v (conns=%d)", got, conns)
+	}
+
+	// Without MaxEvents, reconnection continues until the handler fails.
+	got = nil
+	stop := errors.New("stop")
+	opt.MaxEvents = 0
+	err := client.StreamFactsWithOptions(ctx, "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		if len(got) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handler error to stop reconnection, got %v", err)
+	}
+	if len(got) != 3 || got[0] != 21 || got[1] != 31 || got[2] != 41 {
+		t.Fatalf("expected events from three reconnections, got %v", got)
+	}
+}
+
+// TestStreamFactsWithOptions_IdleTimeout verifies that a connection on
+// which nothing arrives is terminated with ErrStreamIdle.
+func TestStreamFactsWithOptions_IdleTimeout(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": ping\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{IdleTimeout: 50 * time.Millisecond}}
+	start := time.Now()
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		return nil
+	})
+	if !errors.Is(err, ErrStreamIdle) {
+		t.Fatalf("expected ErrStreamIdle, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("idle timeout took too long: %v", time.Since(start))
+	}
+}
+
+// TestStreamFactsWithOptions_IdleTimeoutWithMaxStreamBytes verifies that
+// keepalives still reset the idle timer when MaxStreamBytes is also set.
+func TestStreamFactsWithOptions_IdleTimeoutWithMaxStreamBytes(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 10; i++ {
+			_, _ = w.Write([]byte(": ping\n\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", CursorID: 1})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{IdleTimeout: 100 * time.Millisecond, MaxStreamBytes: 1 << 20}}
+	chunks := 0
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		chunks++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected clean termination, got %v", err)
+	}
+	if chunks != 1 {
+		t.Fatalf("expected 1 chunk, got %d", chunks)
+	}
+}
+
+// TestStreamFactsWithOptions_LastCursor verifies that LastCursor holds the
+// cursor of the last chunk the handler accepted when the stream fails,
+// skipping chunks without a cursor.
+func TestStreamFactsWithOptions_LastCursor(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := int64(1); i <= 3; i++ {
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorUpdatedUTC: t0.Add(time.Duration(i) * time.Minute), CursorID: i})
+			if i == 2 {
+				writeSSEEvent(t, w, "facts", FactsStreamChunk{})
+			}
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var last Cursor
+	errStop := errors.New("stop")
+	err := client.StreamFactsWithOptions(context.Background(), "p_123",
+		FactsStreamOptions{StreamOptions: StreamOptions{LastCursor: &last}},
+		func(ctx context.Context, chunk *FactsStreamChunk) error {
+			if chunk.CursorID == 3 {
+				return errStop
+			}
+			return nil
+		})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if last.ID != 2 || !last.UpdatedUTC.Equal(t0.Add(2*time.Minute)) {
+		t.Fatalf("expected cursor of chunk 2, got %+v", last)
+	}
+}
+
+// TestStreamFactsWithOptions_SnapshotReset verifies that the first chunk
+// of every connection is flagged as a snapshot and that FactsView.Apply
+// then drops facts deleted while disconnected instead of keeping them.
+func TestStreamFactsWithOptions_SnapshotReset(t *testing.T) {
+	now := time.Now().UTC()
+	conns := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conns++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if conns == 1 {
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{Items: []FactItem{{ID: 1, UpdatedUTC: now}, {ID: 2, UpdatedUTC: now}}})
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{Items: []FactItem{{ID: 3, UpdatedUTC: now}}})
+			return
+		}
+		// Fact 2 was deleted while the client was disconnected.
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{Items: []FactItem{{ID: 1, UpdatedUTC: now}, {ID: 3, UpdatedUTC: now}}})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	view := NewFactsView()
+	var snapshots []bool
+	var last FactsChange
+	stop := errors.New("stop")
+	opt := FactsStreamOptions{Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond}}
+	err := client.StreamFactsWithOptions(ctx, "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		snapshots = append(snapshots, IsSnapshotChunk(ctx))
+		last = view.Apply(ctx, chunk)
+		if len(snapshots) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if len(snapshots) != 3 || !snapshots[0] || snapshots[1] || !snapshots[2] {
+		t.Fatalf("expected snapshot flags [true false true], got %v", snapshots)
+	}
+	if !sameIDs(last.Removed, 2) || len(last.Added) != 0 || len(last.Changed) != 0 {
+		t.Fatalf("expected fact 2 removed on reconnect, got %+v", last)
+	}
+	if !sameIDs(view.Items(), 1, 3) {
+		t.Fatalf("expected facts 1 and 3 in view, got %v", factIDs(view.Items()))
+	}
+
+	if IsSnapshotChunk(context.Background()) {
+		t.Fatalf("expected plain contexts not to be flagged")
+	}
+}
@@ -0,0 +1,251 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FactsChange is the effect of one chunk or snapshot on a FactsView.
+// Every slice is ordered by (UpdatedUTC, ID) and is empty, not nil, when
+// nothing of that kind happened.
+type FactsChange struct {
+	// Added holds facts that were not in the view before.
+	Added []FactItem
+
+	// Changed holds the new versions of facts already in the view whose
+	// content differs from the stored version.
+	Changed []FactItem
+
+	// Removed holds the last stored versions of facts that left the view.
+	// Only ApplySnapshot removes facts: stream and updates chunks carry
+	// upserts only.
+	Removed []FactItem
+}
+
+// Empty reports whether the change set has no entries.
+func (ch FactsChange) Empty() bool {
+	return len(ch.Added) == 0 && len(ch.Changed) == 0 && len(ch.Removed) == 0
+}
+
+// FactsView is an in-memory live view of a profile's facts, built from a
+// snapshot and kept current with stream or updates chunks. Each apply
+// returns what changed, so that consumers can update incrementally.
+//
+// A FactsView is safe for concurrent use.
+type FactsView struct {
+	mu     sync.Mutex
+	items  map[int64]FactItem
+	cursor Cursor
+}
+
+// NewFactsView returns an empty FactsView.
+func NewFactsView() *FactsView {
+	return &FactsView{items: make(map[int64]FactItem)}
+}
+
+// ApplyChunk upserts the items of chunk (a FactsStreamChunk or the items
+// of a FactsUpdatesResponse) and returns the added and changed facts.
+//
+// An item older than the stored version of the same fact (by
+// UpdatedUTC), or identical to it, is ignored, so replayed chunks after a
+// reconnection produce no changes. A nil chunk is a no-op.
+func (v *FactsView) ApplyChunk(chunk *FactsStreamChunk) FactsChange {
+	ch := newFactsChange()
+	if chunk == nil {
+		return ch
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, f := range chunk.Items {
+		old, ok := v.items[f.ID]
+		switch {
+		case !ok:
+			ch.Added = append(ch.Added, f)
+		case f.UpdatedUTC.Before(old.UpdatedUTC) || factItemsEqual(old, f):
+			continue
+		default:
+			ch.Changed = append(ch.Changed, f)
+		}
+		v.items[f.ID] = f
+	}
+	v.advance(chunk.CursorUpdatedUTC, chunk.CursorID)
+
+	ch.sort()
+	return ch
+}
+
+// Apply applies a chunk received by a FactsStreamHandler with ctx: the
+// initial snapshot of a connection (see IsSnapshotChunk) replaces the
+// content of the view as with ApplySnapshot, so that facts deleted while
+// the stream was disconnected are reported as Removed; any other chunk is
+// merged as with ApplyChunk.
+func (v *FactsView) Apply(ctx context.Context, chunk *FactsStreamChunk) FactsChange {
+	if IsSnapshotChunk(ctx) {
+		return v.ApplySnapshot(chunk)
+	}
+	return v.ApplyChunk(chunk)
+}
+
+// StreamFactsIntoView streams the facts of proID into view until the
+// stream ends, so that callers get a continuously updated view without
+// writing a handler. Every chunk is applied with Apply: the initial
+// snapshot of each connection replaces the view content, later chunks are
+// merged. Readers use Items, Len and Cursor concurrently.
+//
+// opt is passed to StreamFactsWithOptions; set opt.Reconnect to keep the
+// view live across server closes and transient errors. The method blocks
+// and returns the error that ended the stream (ctx.Err() after
+// cancellation, nil when the server closes it and Reconnect is nil).
+func (c *Client) StreamFactsIntoView(
+	ctx context.Context,
+	proID string,
+	view *FactsView,
+	opt FactsStreamOptions,
+) error {
+	if view == nil {
+		return errors.New("StreamFactsIntoView: view must not be nil")
+	}
+	return c.streamFacts(ctx, "StreamFactsIntoView", proID, opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		view.Apply(ctx, chunk)
+		return nil
+	})
+}
+
+// ApplySnapshot replaces the content of the view with snapshot: facts
+// missing from it are reported as Removed, the others as with ApplyChunk
+// (except that older versions replace newer ones, since the snapshot is
+// authoritative). A nil snapshot is a no-op.
+func (v *FactsView) ApplySnapshot(snapshot *FactsItemsResponse) FactsChange {
+	ch := newFactsChange()
+	if snapshot == nil {
+		return ch
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	next := make(map[int64]FactItem, len(snapshot.Items))
+	for _, f := range snapshot.Items {
+		next[f.ID] = f
+		old, ok := v.items[f.ID]
+		switch {
+		case !ok:
+			ch.Added = append(ch.Added, f)
+		case !factItemsEqual(old, f):
+			ch.Changed = append(ch.Changed, f)
+		}
+	}
+	for id, old := range v.items {
+		if _, ok := next[id]; !ok {
+			ch.Removed = append(ch.Removed, old)
+		}
+	}
+	v.items = next
+	v.cursor = Cursor{}
+	v.advance(snapshot.CursorUpdatedUTC, snapshot.CursorID)
+
+	ch.sort()
+	return ch
+}
+
+// Items returns a copy of the facts in the view, ordered by
+// (UpdatedUTC, ID).
+func (v *FactsView) Items() []FactItem {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := make([]FactItem, 0, len(v.items))
+	for _, f := range v.items {
+		out = append(out, f)
+	}
+	sortFactItems(out)
+	return out
+}
+
+// Len returns the number of facts in the view.
+func (v *FactsView) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.items)
+}
+
+// Cursor returns the greatest cursor applied so far, suitable for
+// resuming the stream or updates polling.
+func (v *FactsView) Cursor() Cursor {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.cursor
+}
+
+// advance moves the view cursor forward; v.mu must be held.
+func (v *FactsView) advance(updatedUTC time.Time, id int64) {
+	if cur := (Cursor{UpdatedUTC: updatedUTC, ID: id}); cur.After(v.cursor) {
+		v.cursor = cur
+	}
+}
+
+// newFactsChange returns a FactsChange with empty, non-nil slices.
+func newFactsChange() FactsChange {
+	return FactsChange{Added: []FactItem{}, Changed: []FactItem{}, Removed: []FactItem{}}
+}
+
+// sort orders every slice of ch by (UpdatedUTC, ID).
+func (ch FactsChange) sort() {
+	sortFactItems(ch.Added)
+	sortFactItems(ch.Changed)
+	sortFactItems(ch.Removed)
+}
+
+// sortFactItems orders items by (UpdatedUTC, ID), the server's order.
+func sortFactItems(items []FactItem) {
+	slices.SortFunc(items, func(a, b FactItem) int {
+		if c := a.UpdatedUTC.Compare(b.UpdatedUTC); c != 0 {
+			return c
+		}
+		switch {
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		}
+		return 0
+	})
+}
+
+// factItemsEqual reports whether a and b hold the same content, comparing
+// times by instant and optional fields by value.
+func factItemsEqual(a, b FactItem) bool {
+	return a.ID == b.ID &&
+		a.ProID == b.ProID &&
+		a.FactText == b.FactText &&
+		a.FactHash == b.FactHash &&
+		a.Status == b.Status &&
+		equalPtr(a.FalseReason, b.FalseReason) &&
+		a.CreatedUTC.Equal(b.CreatedUTC) &&
+		a.LastSeenUTC.Equal(b.LastSeenUTC) &&
+		a.UpdatedUTC.Equal(b.UpdatedUTC) &&
+		equalPtr(a.ReviewStatus, b.ReviewStatus) &&
+		equalTimePtr(a.ReviewUpdatedUTC, b.ReviewUpdatedUTC) &&
+		a.IsWritable == b.IsWritable
+}
+
+// equalPtr reports whether a and b are both nil or point to equal values.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalTimePtr is equalPtr for times, comparing instants.
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,168 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// factIDs returns the ids of items in order.
+func factIDs(items []FactItem) []int64 {
+	ids := make([]int64, len(items))
+	for i, f := range items {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// sameIDs reports whether got holds exactly want, in order.
+func sameIDs(got []FactItem, want ...int64) bool {
+	ids := factIDs(got)
+	if len(ids) != len(want) {
+		return false
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestFactsView_ChangeSets verifies the change set reported across a
+// snapshot, successive chunks, a replayed chunk and a new snapshot.
+func TestFactsView_ChangeSets(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ok := "ok"
+	v := NewFactsView()
+
+	ch := v.ApplySnapshot(&FactsItemsResponse{
+		CursorUpdatedUTC: t0,
+		CursorID:         2,
+		Items: []FactItem{
+			{ID: 2, FactText: "b", UpdatedUTC: t0},
+			{ID: 1, FactText: "a", UpdatedUTC: t0},
+		},
+	})
+	if !sameIDs(ch.Added, 1, 2) || len(ch.Changed) != 0 || len(ch.Removed) != 0 {
+		t.Fatalf("unexpected snapshot change: %+v", ch)
+	}
+
+	chunk := &FactsStreamChunk{
+		CursorUpdatedUTC: t0.Add(time.Minute),
+		CursorID:         3,
+		Items: []FactItem{
+			{ID: 1, FactText: "a", UpdatedUTC: t0.Add(time.Minute), ReviewStatus: &ok},
+			{ID: 3, FactText: "c", UpdatedUTC: t0.Add(time.Minute)},
+		},
+	}
+	ch = v.ApplyChunk(chunk)
+	if !sameIDs(ch.Added, 3) || !sameIDs(ch.Changed, 1) || len(ch.Removed) != 0 {
+		t.Fatalf("unexpected chunk change: %+v", ch)
+	}
+
+	// Replaying the same chunk, or an older version, changes nothing.
+	if ch := v.ApplyChunk(chunk); !ch.Empty() {
+		t.Fatalf("expected no change for a replayed chunk, got %+v", ch)
+	}
+	stale := &FactsStreamChunk{Items: []FactItem{{ID: 1, FactText: "old", UpdatedUTC: t0}}}
+	if ch := v.ApplyChunk(stale); !ch.Empty() {
+		t.Fatalf("expected no change for a stale item, got %+v", ch)
+	}
+
+	if cur := v.Cursor(); cur.ID != 3 || !cur.UpdatedUTC.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("unexpected cursor: %+v", cur)
+	}
+	if items := v.Items(); !sameIDs(items, 2, 1, 3) || items[1].ReviewStatus == nil {
+		t.Fatalf("unexpected view: %+v", items)
+	}
+
+	ch = v.ApplySnapshot(&FactsItemsResponse{
+		Items: []FactItem{{ID: 3, FactText: "c", UpdatedUTC: t0.Add(time.Minute)}},
+	})
+	if len(ch.Added) != 0 || len(ch.Changed) != 0 || !sameIDs(ch.Removed, 2, 1) {
+		t.Fatalf("unexpected resnapshot change: %+v", ch)
+	}
+	if v.Len() != 1 {
+		t.Fatalf("expected 1 fact after resnapshot, got %d", v.Len())
+	}
+}
+
+// TestFactsView_Concurrent exercises concurrent applies and reads; run
+// with -race.
+func TestFactsView_Concurrent(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := NewFactsView()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := int64(g*50 + i)
+				v.ApplyChunk(&FactsStreamChunk{
+					CursorUpdatedUTC: t0.Add(time.Duration(id) * time.Second),
+					CursorID:         id,
+					Items:            []FactItem{{ID: id, UpdatedUTC: t0.Add(time.Duration(id) * time.Second)}},
+				})
+				_ = v.Items()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if v.Len() != 200 {
+		t.Fatalf("expected 200 facts, got %d", v.Len())
+	}
+	if cur := v.Cursor(); cur.ID != 199 {
+		t.Fatalf("expected cursor id 199, got %+v", cur)
+	}
+}
+
+// TestStreamFactsIntoView verifies that streamed chunks land in the view
+// and that a reconnection's snapshot replaces its content.
+func TestStreamFactsIntoView(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	conns := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conns++
+		switch conns {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{Items: []FactItem{{ID: 1, UpdatedUTC: now}, {ID: 2, UpdatedUTC: now}}})
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorUpdatedUTC: now, CursorID: 3, Items: []FactItem{{ID: 3, UpdatedUTC: now, FactText: "new"}}})
+		case 2:
+			// Fact 2 was deleted while the client was disconnected.
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorUpdatedUTC: now, CursorID: 3,
+				Items: []FactItem{{ID: 1, UpdatedUTC: now}, {ID: 3, UpdatedUTC: now, FactText: "new"}}})
+		default:
+			http.Error(w, `{"error":"gone"}`, http.StatusGone)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	view := NewFactsView()
+	opt := FactsStreamOptions{Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond}}
+	err := client.StreamFactsIntoView(context.Background(), "p_123", view, opt)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusGone {
+		t.Fatalf("expected the 410 to end the stream, got %v", err)
+	}
+	if conns != 3 {
+		t.Fatalf("expected 3 connections, got %d", conns)
+	}
+	items := view.Items()
+	if !sameIDs(items, 1, 3) || items[1].FactText != "new" {
+		t.Fatalf("expected facts 1 and 3 in view, got %+v", items)
+	}
+	if view.Cursor().ID != 3 {
+		t.Fatalf("expected the view cursor of the last snapshot, got %+v", view.Cursor())
+	}
+}
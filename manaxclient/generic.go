@@ -0,0 +1,99 @@
+package manaxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GetJSON performs GET path?query against c with the client's standard
+// headers and decodes the JSON response body into a new T.
+//
+// It is the building block of the typed GET methods and can be used to
+// call endpoints the client does not wrap yet. Non-2xx responses are
+// returned as *APIError. An empty 2xx body yields a zero T.
+//
+// Decoding uses encoding/json, so a T (or any nested field type) that
+// implements json.Unmarshaler is decoded by its own UnmarshalJSON.
+func GetJSON[T any](ctx context.Context, c *Client, path string, query url.Values) (*T, error) {
+	ctx = withDefaultOperation(ctx, "GetJSON")
+	if c == nil {
+		return nil, errors.New("GetJSON: client must not be nil")
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.applyHeaders(req, nil)
+
+	var out T
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PostJSON marshals body as JSON, sends it with POST path?query and
+// Content-Type application/json, and decodes the JSON response body into
+// a new TResp. Errors follow the same rules as GetJSON.
+func PostJSON[TReq any, TResp any](ctx context.Context, c *Client, path string, query url.Values, body TReq) (*TResp, error) {
+	ctx = withDefaultOperation(ctx, "PostJSON")
+	if c == nil {
+		return nil, errors.New("PostJSON: client must not be nil")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %T: %w", body, err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, path, query, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	c.applyHeaders(req, h)
+
+	var out TResp
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBytes performs GET path?query against c and returns the raw response
+// body, for endpoints serving binary or non-JSON content.
+//
+// accept is sent as the Accept header; if empty, "*/*" is used so that
+// the request does not advertise JSON. Non-2xx responses are returned as
+// *APIError.
+func GetBytes(ctx context.Context, c *Client, path string, query url.Values, accept string) ([]byte, error) {
+	ctx = withDefaultOperation(ctx, "GetBytes")
+	if c == nil {
+		return nil, errors.New("GetBytes: client must not be nil")
+	}
+	if accept = strings.TrimSpace(accept); accept == "" {
+		accept = "*/*"
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	h.Set("Accept", accept)
+	c.applyHeaders(req, h)
+
+	data, _, err := c.doRaw(req)
+	return data, err
+}
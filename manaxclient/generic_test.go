@@ -0,0 +1,185 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestGetJSON verifies decoding into a package response type and into a
+// caller-defined type, and that query and headers are applied.
+func TestGetJSON(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		if r.Header.Get("X-Pro-Id") != "p_123" || r.Header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/facts/items/snapshot":
+			if r.URL.Query().Get("proId") != "p_123" {
+				t.Errorf("unexpected query: %v", r.URL.Query())
+			}
+			_ = json.NewEncoder(w).Encode(FactsItemsResponse{ProID: "p_123", CursorID: 7, Items: []FactItem{{ID: 7}}})
+		case "/api/custom":
+			_, _ = w.Write([]byte(`{"name":"x","count":3}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+	client.SetAuth("p_123", "tok")
+
+	facts, err := GetJSON[FactsItemsResponse](context.Background(), client, "/api/facts/items/snapshot", url.Values{"proId": {"p_123"}})
+	if err != nil {
+		t.Fatalf("GetJSON returned error: %v", err)
+	}
+	if facts.CursorID != 7 || len(facts.Items) != 1 {
+		t.Fatalf("unexpected facts response: %#v", facts)
+	}
+
+	type custom struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	got, err := GetJSON[custom](context.Background(), client, "/api/custom", nil)
+	if err != nil {
+		t.Fatalf("GetJSON returned error: %v", err)
+	}
+	if got.Name != "x" || got.Count != 3 {
+		t.Fatalf("unexpected custom response: %#v", got)
+	}
+}
+
+// TestPostJSON verifies the request body and Content-Type, the decoded
+// response, and that non-2xx responses surface as *APIError.
+func TestPostJSON(t *testing.T) {
+	type request struct {
+		Value string `json:"value"`
+	}
+	type response struct {
+		Echo string `json:"echo"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var in request
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode body failed: %v", err)
+		}
+		if r.URL.Path == "/api/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"bad value"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response{Echo: in.Value})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	got, err := PostJSON[request, response](context.Background(), client, "/api/echo", nil, request{Value: "hi"})
+	if err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if got.Echo != "hi" {
+		t.Fatalf("unexpected response: %#v", got)
+	}
+
+	_, err = PostJSON[request, response](context.Background(), client, "/api/fail", nil, request{Value: "x"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "bad value" {
+		t.Fatalf("expected 400 APIError with message, got %v", err)
+	}
+}
+
+// upperName is a test type whose UnmarshalJSON transforms the payload.
+type upperName struct {
+	Value string
+}
+
+func (u *upperName) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	u.Value = strings.ToUpper(s)
+	return nil
+}
+
+// TestGetJSON_CustomUnmarshaler verifies that custom UnmarshalJSON
+// methods fire both for the top-level type and for nested fields.
+func TestGetJSON_CustomUnmarshaler(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/name":
+			_, _ = w.Write([]byte(`"alice"`))
+		default:
+			_, _ = w.Write([]byte(`{"owner":"bob"}`))
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	top, err := GetJSON[upperName](context.Background(), client, "/api/name", nil)
+	if err != nil {
+		t.Fatalf("GetJSON returned error: %v", err)
+	}
+	if top.Value != "ALICE" {
+		t.Fatalf("expected top-level UnmarshalJSON to run, got %q", top.Value)
+	}
+
+	type wrapper struct {
+		Owner upperName `json:"owner"`
+	}
+	nested, err := PostJSON[struct{}, wrapper](context.Background(), client, "/api/owner", nil, struct{}{})
+	if err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if nested.Owner.Value != "BOB" {
+		t.Fatalf("expected nested UnmarshalJSON to run, got %q", nested.Owner.Value)
+	}
+}
+
+// TestGetBytes verifies that raw GETs do not advertise JSON and return
+// the body unchanged.
+func TestGetBytes(t *testing.T) {
+	payload := []byte{0x52, 0x49, 0x46, 0x46, 0x00, 0xff}
+	var accepts []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		accepts = append(accepts, r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write(payload)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	got, err := GetBytes(context.Background(), client, "/api/speech/audio", nil, "")
+	if err != nil {
+		t.Fatalf("GetBytes returned error: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Fatalf("unexpected body: %v", got)
+	}
+	if _, err := GetBytes(context.Background(), client, "/api/speech/audio", nil, "audio/wav"); err != nil {
+		t.Fatalf("GetBytes returned error: %v", err)
+	}
+
+	if len(accepts) != 2 || accepts[0] != "*/*" || accepts[1] != "audio/wav" {
+		t.Fatalf("expected Accept */* then audio/wav, got %q", accepts)
+	}
+}
@@ -0,0 +1,187 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// LiveMatches is a batteries-included live collection of the matches of
+// one profile and direction: Start loads a snapshot and then follows the
+// matches stream, keeping a deduplicated view that can be read with Items
+// or observed with Subscribe.
+//
+// A LiveMatches is safe for concurrent use. It is started at most once.
+type LiveMatches struct {
+	client    *Client
+	proID     string
+	direction MatchingDirection
+	filter    MatchesFilter
+
+	// Policy controls reconnection after the server closes the stream or
+	// a transient error occurs (see ManagedMatchesStream). Set it before
+	// calling Start.
+	Policy ReconnectPolicy
+
+	mu      sync.Mutex
+	items   map[int64]MatchItem
+	loaded  bool
+	started bool
+	done    bool
+	subs    []chan []MatchItem
+}
+
+// NewLiveMatches returns a LiveMatches for proID and direction whose
+// snapshot and stream requests use filter. Nothing is fetched until
+// Start is called.
+func (c *Client) NewLiveMatches(proID string, direction MatchingDirection, filter MatchesFilter) (*LiveMatches, error) {
+	proID = c.resolveProID(context.Background(), proID)
+	if proID == "" {
+		return nil, errors.New("NewLiveMatches: proID must not be empty")
+	}
+	if direction == "" {
+		return nil, errors.New("NewLiveMatches: direction must not be empty")
+	}
+	return &LiveMatches{
+		client:    c,
+		proID:     proID,
+		direction: direction,
+		filter:    filter,
+		items:     make(map[int64]MatchItem),
+	}, nil
+}
+
+// Start loads the matches snapshot and then streams updates into the
+// collection, reconnecting according to Policy, until ctx is cancelled or
+// the stream fails permanently. It blocks and returns the error that
+// ended it (ctx.Err() after cancellation); the subscription channels are
+// closed when it returns.
+//
+// Every reconnection resumes from the last applied cursor, so no update
+// is missed or applied twice. Start fails if it was already called.
+func (l *LiveMatches) Start(ctx context.Context) error {
+	l.mu.Lock()
+	if l.started {
+		l.mu.Unlock()
+		return errors.New("LiveMatches: already started")
+	}
+	l.started = true
+	l.mu.Unlock()
+
+	defer l.finish()
+	ctx = withOperation(ctx, "LiveMatches")
+	return l.client.runManagedMatches(ctx, "LiveMatches", l.proID, l.direction, l.filter,
+		NewMemoryCursorStore(), l.Policy, l.apply)
+}
+
+// Items returns a copy of the current view: one entry per match ID, in
+// its latest version, ordered by (UpdatedUTC, ID). It is empty until the
+// snapshot has been loaded.
+func (l *LiveMatches) Items() []MatchItem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.view()
+}
+
+// Subscribe returns a channel receiving the whole view (as returned by
+// Items) every time it changes: once after the snapshot is loaded, then
+// after every stream chunk that adds or modifies a match. If the view is
+// already loaded, it is sent right away.
+//
+// The channel holds only the latest view: a slow receiver skips
+// intermediate versions but never blocks the stream. It is closed when
+// Start returns; subscribing afterwards yields a closed channel.
+func (l *LiveMatches) Subscribe() <-chan []MatchItem {
+	ch := make(chan []MatchItem, 1)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done {
+		close(ch)
+		return ch
+	}
+	if l.loaded {
+		ch <- l.view()
+	}
+	l.subs = append(l.subs, ch)
+	return ch
+}
+
+// apply merges items into the view and notifies subscribers of a change.
+// The snapshot always counts as a change, even when empty.
+func (l *LiveMatches) apply(items []MatchItem) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	changed := !l.loaded
+	l.loaded = true
+	for _, m := range items {
+		old, ok := l.items[m.ID]
+		if ok && (m.UpdatedUTC.Before(old.UpdatedUTC) || matchItemsEqual(old, m)) {
+			continue
+		}
+		l.items[m.ID] = m
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	view := l.view()
+	for _, ch := range l.subs {
+		// Replace a view the subscriber has not received yet.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- view
+	}
+	return nil
+}
+
+// finish closes the subscription channels once Start has returned.
+func (l *LiveMatches) finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.done = true
+	for _, ch := range l.subs {
+		close(ch)
+	}
+	l.subs = nil
+}
+
+// view returns a sorted copy of the items; l.mu must be held.
+func (l *LiveMatches) view() []MatchItem {
+	out := make([]MatchItem, 0, len(l.items))
+	for _, m := range l.items {
+		out = append(out, m)
+	}
+	slices.SortFunc(out, func(a, b MatchItem) int {
+		if c := a.UpdatedUTC.Compare(b.UpdatedUTC); c != 0 {
+			return c
+		}
+		switch {
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+// matchItemsEqual reports whether a and b hold the same content, comparing
+// times as instants.
+func matchItemsEqual(a, b MatchItem) bool {
+	return a.ID == b.ID &&
+		a.ProID == b.ProID &&
+		a.TargetProID == b.TargetProID &&
+		a.Direction == b.Direction &&
+		a.Score == b.Score &&
+		a.Rationale == b.Rationale &&
+		a.ModelID == b.ModelID &&
+		a.CreatedUTC.Equal(b.CreatedUTC) &&
+		a.UpdatedUTC.Equal(b.UpdatedUTC)
+}
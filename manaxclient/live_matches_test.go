@@ -0,0 +1,83 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestLiveMatches verifies the initial load from the snapshot, the
+// emission of the merged view after a stream chunk, and the shutdown on
+// cancellation.
+func TestLiveMatches(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{
+				ProID: "p_123", CursorUpdatedUTC: t0, CursorID: 2,
+				Items: []MatchItem{{ID: 1, Score: 0.5, UpdatedUTC: t0}, {ID: 2, Score: 0.6, UpdatedUTC: t0}},
+			})
+		case "/api/matches/items/stream":
+			if got := r.URL.Query().Get("sinceId"); got != "2" {
+				t.Errorf("expected the stream to resume after the snapshot, got sinceId=%s", got)
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			t1 := t0.Add(time.Minute)
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+				ProID: "p_123", CursorUpdatedUTC: t1, CursorID: 3,
+				Items: []MatchItem{{ID: 1, Score: 0.9, UpdatedUTC: t1}, {ID: 3, Score: 0.7, UpdatedUTC: t1}},
+			})
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	live, err := client.NewLiveMatches("p_123", MatchingDirectionOffer, MatchesFilter{})
+	if err != nil {
+		t.Fatalf("NewLiveMatches failed: %v", err)
+	}
+	updates := live.Subscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- live.Start(ctx) }()
+
+	first := <-updates
+	if len(first) != 2 || first[0].ID != 1 || first[1].ID != 2 {
+		t.Fatalf("expected the snapshot view, got %+v", first)
+	}
+	second := <-updates
+	if len(second) != 3 || second[0].ID != 2 || second[1].ID != 1 || second[1].Score != 0.9 || second[2].ID != 3 {
+		t.Fatalf("expected the merged view, got %+v", second)
+	}
+	if items := live.Items(); len(items) != 3 {
+		t.Fatalf("expected Items to match the last view, got %+v", items)
+	}
+
+	// A late subscriber gets the current view right away.
+	if late := <-live.Subscribe(); len(late) != 3 {
+		t.Fatalf("expected the current view for a late subscriber, got %+v", late)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from Start, got %v", err)
+	}
+	if _, ok := <-updates; ok {
+		t.Fatalf("expected the subscription to be closed")
+	}
+	if err := live.Start(context.Background()); err == nil {
+		t.Fatalf("expected a second Start to fail")
+	}
+}
@@ -0,0 +1,375 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReconnectInitialBackoff is the first reconnect delay used
+	// when ReconnectPolicy.InitialBackoff is not set.
+	DefaultReconnectInitialBackoff = time.Second
+
+	// DefaultReconnectMaxBackoff caps the reconnect delay when
+	// ReconnectPolicy.MaxBackoff is not set.
+	DefaultReconnectMaxBackoff = 30 * time.Second
+)
+
+// CursorStore persists stream cursors so that a stream can resume where
+// it stopped, across reconnections and process restarts.
+//
+// Keys are opaque strings chosen by the client (for example
+// "matches:p_123:Offer"). Implementations must be safe for concurrent use.
+type CursorStore interface {
+	// LoadCursor returns the cursor stored under key. ok is false when
+	// nothing has been stored yet.
+	LoadCursor(ctx context.Context, key string) (cur Cursor, ok bool, err error)
+
+	// SaveCursor stores cur under key, replacing any previous value.
+	SaveCursor(ctx context.Context, key string, cur Cursor) error
+}
+
+// MemoryCursorStore is an in-memory CursorStore. It is useful for tests
+// and for processes that only need resumption across reconnections.
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]Cursor
+}
+
+// NewMemoryCursorStore returns an empty MemoryCursorStore.
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{cursors: make(map[string]Cursor)}
+}
+
+// LoadCursor implements CursorStore.
+func (s *MemoryCursorStore) LoadCursor(_ context.Context, key string) (Cursor, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s.cursors[key]
+	return cur, ok, nil
+}
+
+// SaveCursor implements CursorStore.
+func (s *MemoryCursorStore) SaveCursor(_ context.Context, key string, cur Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[key] = cur
+	return nil
+}
+
+// ReconnectPolicy controls how long-running streams reconnect after the
+// server closes the connection or a transient error occurs.
+type ReconnectPolicy struct {
+	// MaxAttempts is the maximum number of consecutive reconnections
+	// without receiving any data before giving up. 0 means unlimited.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first reconnection.
+	// If <= 0, DefaultReconnectInitialBackoff is used.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponentially growing delay.
+	// If <= 0, DefaultReconnectMaxBackoff is used.
+	MaxBackoff time.Duration
+}
+
+// reconnectBackoff computes exponential delays for a ReconnectPolicy.
+type reconnectBackoff struct {
+	policy   ReconnectPolicy
+	attempts int
+	cur      time.Duration
+}
+
+// newReconnectBackoff normalizes p into a reconnectBackoff.
+func newReconnectBackoff(p ReconnectPolicy) *reconnectBackoff {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultReconnectInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultReconnectMaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return &reconnectBackoff{policy: p}
+}
+
+// next returns the delay before the next attempt and false once
+// MaxAttempts consecutive attempts have been used up.
+func (b *reconnectBackoff) next() (time.Duration, bool) {
+	if b.policy.MaxAttempts > 0 && b.attempts >= b.policy.MaxAttempts {
+		return 0, false
+	}
+	b.attempts++
+	if b.cur == 0 {
+		b.cur = b.policy.InitialBackoff
+	} else {
+		b.cur *= 2
+		if b.cur > b.policy.MaxBackoff {
+			b.cur = b.policy.MaxBackoff
+		}
+	}
+	return b.cur, true
+}
+
+// reset restarts the sequence after a successful exchange of data.
+func (b *reconnectBackoff) reset() {
+	b.attempts = 0
+	b.cur = 0
+}
+
+// isPermanentStreamError reports whether err must stop a reconnect loop:
+// client errors (4xx except 408/429) and non-SSE answers will not go away
+// by retrying.
+func isPermanentStreamError(err error) bool {
+	if errors.Is(err, ErrUnexpectedContentType) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500
+}
+
+// matchesCursorKey is the CursorStore key used for a matches stream.
+func matchesCursorKey(proID string, direction MatchingDirection) string {
+	return "matches:" + proID + ":" + string(direction)
+}
+
+// ManagedMatchesStream is the "just give me items forever" API: it
+// delivers every new or changed match for proID and direction on the
+// returned item channel until ctx is cancelled.
+//
+// Internally it:
+//   - loads the cursor from store (key "matches:<proID>:<direction>");
+//   - if none is stored, bootstraps with GetMatchesSnapshot, emits the
+//     snapshot items and saves the snapshot cursor;
+//   - streams updates with StreamMatches, dropping items at or before the
+//     current cursor (duplicates after reconnection);
+//   - saves the cursor after every chunk whose items were all delivered;
+//   - reconnects with exponential backoff according to policy (or after
+//     the server's "retry:" delay, if it sent one) after the server
+//     closes the stream or a transient error occurs, as StreamMatches
+//     does with MatchesStreamOptions.Reconnect. Each reconnection
+//     consumes one unit of the ctx retry budget, if any (see
+//     WithRetryBudget).
+//
+// Both channels are closed when the stream terminates. The error channel
+// receives at most one value: the error that ended the stream (permanent
+// API error, exhausted reconnect attempts, cursor store failure). Context
+// cancellation closes the channels without sending an error.
+func (c *Client) ManagedMatchesStream(
+	ctx context.Context,
+	proID string,
+	direction MatchingDirection,
+	filter MatchesFilter,
+	store CursorStore,
+	policy ReconnectPolicy,
+) (<-chan *MatchItem, <-chan error) {
+	items := make(chan *MatchItem)
+	errs := make(chan error, 1)
+
+	fail := func(err error) (<-chan *MatchItem, <-chan error) {
+		errs <- err
+		close(items)
+		close(errs)
+		return items, errs
+	}
+
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return fail(errors.New("ManagedMatchesStream: proID must not be empty"))
+	}
+	if direction == "" {
+		return fail(errors.New("ManagedMatchesStream: direction must not be empty"))
+	}
+	if store == nil {
+		return fail(errors.New("ManagedMatchesStream: store must not be nil"))
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+
+		deliver := func(batch []MatchItem) error {
+			for i := range batch {
+				select {
+				case items <- &batch[i]:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		}
+		err := c.runManagedMatches(ctx, "ManagedMatchesStream", proID, direction, filter, store, policy, deliver)
+		if err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return items, errs
+}
+
+// MatchesBothHandler receives the items StreamMatchesBoth delivers for
+// one direction.
+type MatchesBothHandler func(ctx context.Context, direction MatchingDirection, items []MatchItem) error
+
+// StreamMatchesBoth follows the matches of proID in both directions at
+// once, running the ManagedMatchesStream loop for MatchingDirectionOffer
+// and MatchingDirectionSeek side by side. It blocks until ctx is
+// cancelled or either direction stops, and returns that error.
+//
+// Each direction keeps its own cursor, stored in store under its own key
+// ("matches:<proID>:Offer" and "matches:<proID>:Seek"), and reconnects on
+// its own according to policy: a disconnect of one direction resumes
+// from that direction's cursor and does not interrupt the other.
+//
+// handler receives the snapshot items, then the new items of every
+// stream chunk, tagged with their direction; empty batches are skipped.
+// Calls are serialized, so handler needs no locking. A handler error
+// stops both directions.
+func (c *Client) StreamMatchesBoth(
+	ctx context.Context,
+	proID string,
+	filter MatchesFilter,
+	store CursorStore,
+	policy ReconnectPolicy,
+	handler MatchesBothHandler,
+) error {
+	ctx = withOperation(ctx, "StreamMatchesBoth")
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return errors.New("StreamMatchesBoth: proID must not be empty")
+	}
+	if store == nil {
+		return errors.New("StreamMatchesBoth: store must not be nil")
+	}
+	if handler == nil {
+		return errors.New("StreamMatchesBoth: handler must not be nil")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu         sync.Mutex
+		handlerErr error
+	)
+	directions := []MatchingDirection{MatchingDirectionOffer, MatchingDirectionSeek}
+	errs := make(chan error, len(directions))
+	for _, direction := range directions {
+		deliver := func(items []MatchItem) error {
+			if len(items) == 0 {
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if handlerErr != nil {
+				// The other direction's handler failed: deliver nothing
+				// more while the cancellation propagates.
+				return handlerErr
+			}
+			if err := handler(runCtx, direction, items); err != nil {
+				handlerErr = err
+				cancel()
+				return err
+			}
+			return nil
+		}
+		go func() {
+			errs <- c.runManagedMatches(runCtx, "StreamMatchesBoth", proID, direction, filter, store, policy, deliver)
+		}()
+	}
+
+	// The first direction to stop decides the result; the other one is
+	// then cancelled and only waited for.
+	err := <-errs
+	cancel()
+	for range directions[1:] {
+		<-errs
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if handlerErr != nil {
+		return handlerErr
+	}
+	return err
+}
+
+// runManagedMatches is the body of ManagedMatchesStream and
+// LiveMatches.Start; op is used as error message prefix. deliver receives
+// the snapshot items, then the new items of every stream chunk, and may
+// stop the loop by returning an error.
+func (c *Client) runManagedMatches(
+	ctx context.Context,
+	op string,
+	proID string,
+	direction MatchingDirection,
+	filter MatchesFilter,
+	store CursorStore,
+	policy ReconnectPolicy,
+	deliver func(items []MatchItem) error,
+) error {
+	key := matchesCursorKey(proID, direction)
+
+	cursor, ok, err := store.LoadCursor(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%s: load cursor: %w", op, err)
+	}
+	if !ok {
+		snap, err := c.GetMatchesSnapshot(ctx, proID, direction,
+			filter.MinScore, filter.Limit, filter.MinRationaleLength, filter.MaxRationaleLength)
+		if err != nil {
+			return fmt.Errorf("%s: bootstrap snapshot: %w", op, err)
+		}
+		if err := deliver(snap.Items); err != nil {
+			return err
+		}
+		cursor = Cursor{UpdatedUTC: snap.CursorUpdatedUTC, ID: snap.CursorID}
+		if cursor.UpdatedUTC.IsZero() {
+			// An empty snapshot carries no cursor; the stream endpoint
+			// still requires one, so start from the beginning of time.
+			cursor.UpdatedUTC = time.Unix(0, 0).UTC()
+		}
+		if err := store.SaveCursor(ctx, key, cursor); err != nil {
+			return fmt.Errorf("%s: save cursor: %w", op, err)
+		}
+	}
+
+	// The stream reconnects by itself, resuming from the cursor of the
+	// last handled chunk; the handler filters duplicates and persists
+	// the cursor.
+	streamOpt := filter.streamOptions(direction)
+	streamOpt.Reconnect = &policy
+	return c.streamMatches(ctx, op, proID, cursor, streamOpt,
+		func(ctx context.Context, chunk *MatchesStreamChunk, _ SSEEventMeta) error {
+			fresh := make([]MatchItem, 0, len(chunk.Items))
+			for _, m := range chunk.Items {
+				if (Cursor{UpdatedUTC: m.UpdatedUTC, ID: m.ID}).After(cursor) {
+					fresh = append(fresh, m)
+				}
+			}
+			if err := deliver(fresh); err != nil {
+				return err
+			}
+			next := Cursor{UpdatedUTC: chunk.CursorUpdatedUTC, ID: chunk.CursorID}
+			if next.After(cursor) {
+				cursor = next
+				if err := store.SaveCursor(ctx, key, cursor); err != nil {
+					return fmt.Errorf("%s: save cursor: %w", op, err)
+				}
+			}
+			return nil
+		})
+}
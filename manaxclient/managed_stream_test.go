@@ -0,0 +1,317 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestManagedMatchesStream verifies snapshot bootstrap, reconnection after
+// the server closes the stream, duplicate suppression across connections
+// and cursor persistence.
+func TestManagedMatchesStream(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	t2 := t0.Add(2 * time.Minute)
+
+	var conns int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{
+				ProID:            "p_123",
+				CursorUpdatedUTC: t0,
+				CursorID:         1,
+				Items:            []MatchItem{{ID: 1, UpdatedUTC: t0}},
+			})
+		case "/api/matches/items/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			switch atomic.AddInt32(&conns, 1) {
+			case 1:
+				if got := r.URL.Query().Get("sinceId"); got != "1" {
+					t.Errorf("first connection: expected sinceId=1, got %q", got)
+				}
+				writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+					CursorUpdatedUTC: t1,
+					CursorID:         2,
+					Items:            []MatchItem{{ID: 2, UpdatedUTC: t1}},
+				})
+				// Returning closes the stream: simulated disconnect.
+			default:
+				if got := r.URL.Query().Get("sinceId"); got != "2" {
+					t.Errorf("reconnection: expected sinceId=2, got %q", got)
+				}
+				writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+					CursorUpdatedUTC: t2,
+					CursorID:         3,
+					Items: []MatchItem{
+						{ID: 2, UpdatedUTC: t1},
+						{ID: 3, UpdatedUTC: t2},
+					},
+				})
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			}
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := NewMemoryCursorStore()
+	policy := ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	items, errs := client.ManagedMatchesStream(ctx, "p_123", MatchingDirectionOffer, MatchesFilter{}, store, policy)
+
+	var got []int64
+	for len(got) < 3 {
+		select {
+		case m, ok := <-items:
+			if !ok {
+				t.Fatalf("items channel closed early, got %v, err=%v", got, <-errs)
+			}
+			got = append(got, m.ID)
+		case <-ctx.Done():
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	if got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Fatalf("expected items [1 2 3] without duplicates, got %v", got)
+	}
+
+	cur, ok, _ := store.LoadCursor(ctx, matchesCursorKey("p_123", MatchingDirectionOffer))
+	if !ok || cur.ID != 3 || !cur.UpdatedUTC.Equal(t2) {
+		t.Fatalf("expected persisted cursor (t2, 3), got %#v ok=%v", cur, ok)
+	}
+
+	cancel()
+	for range items {
+	}
+	if err, ok := <-errs; ok && err != nil {
+		t.Fatalf("expected no error after cancellation, got %v", err)
+	}
+	if n := atomic.LoadInt32(&conns); n != 2 {
+		t.Fatalf("expected 2 stream connections, got %d", n)
+	}
+}
+
+// TestManagedMatchesStream_ResumesFromStore verifies that a stored cursor
+// skips the snapshot bootstrap and is used to open the stream.
+func TestManagedMatchesStream_ResumesFromStore(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/matches/items/stream" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			return
+		}
+		if got := r.URL.Query().Get("sinceId"); got != "40" {
+			t.Errorf("expected sinceId=40 from store, got %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+			CursorUpdatedUTC: t0.Add(time.Minute),
+			CursorID:         41,
+			Items:            []MatchItem{{ID: 41, UpdatedUTC: t0.Add(time.Minute)}},
+		})
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := NewMemoryCursorStore()
+	key := matchesCursorKey("p_123", MatchingDirectionSeek)
+	_ = store.SaveCursor(ctx, key, Cursor{UpdatedUTC: t0, ID: 40})
+
+	items, _ := client.ManagedMatchesStream(ctx, "p_123", MatchingDirectionSeek, MatchesFilter{}, store, ReconnectPolicy{})
+	select {
+	case m := <-items:
+		if m == nil || m.ID != 41 {
+			t.Fatalf("unexpected item: %#v", m)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for item")
+	}
+	cancel()
+}
+
+// TestManagedMatchesStream_GivesUp verifies that exhausted reconnect
+// attempts are reported on the error channel.
+func TestManagedMatchesStream_GivesUp(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	store := NewMemoryCursorStore()
+	_ = store.SaveCursor(context.Background(), matchesCursorKey("p_123", MatchingDirectionOffer), Cursor{UpdatedUTC: time.Now(), ID: 1})
+
+	policy := ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	items, errs := client.ManagedMatchesStream(context.Background(), "p_123", MatchingDirectionOffer, MatchesFilter{}, store, policy)
+	for range items {
+	}
+	if err := <-errs; err == nil {
+		t.Fatalf("expected an error after exhausting reconnect attempts")
+	}
+}
+
+// TestStreamMatchesBoth verifies that a disconnect of one direction
+// resumes from that direction's own cursor while the other direction's
+// connection stays open, and that each cursor is stored under its own key.
+func TestStreamMatchesBoth(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := map[string]int64{MatchingDirectionOffer.Query(): 10, MatchingDirectionSeek.Query(): 20}
+
+	var mu sync.Mutex
+	conns := map[string][]string{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		direction := q.Get("direction")
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			id := snapshots[direction]
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{
+				ProID:            "p_123",
+				CursorUpdatedUTC: t0,
+				CursorID:         id,
+				Items:            []MatchItem{{ID: id, UpdatedUTC: t0}},
+			})
+		case "/api/matches/items/stream":
+			mu.Lock()
+			conns[direction] = append(conns[direction], q.Get("sinceId"))
+			n := len(conns[direction])
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			id := snapshots[direction] + int64(n)
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+				ProID:            "p_123",
+				CursorUpdatedUTC: t0.Add(time.Duration(n) * time.Minute),
+				CursorID:         id,
+				Items:            []MatchItem{{ID: id, UpdatedUTC: t0.Add(time.Duration(n) * time.Minute)}},
+			})
+			w.(http.Flusher).Flush()
+			if direction == MatchingDirectionOffer.Query() && n == 1 {
+				// Returning closes the Offer stream: simulated disconnect.
+				return
+			}
+			<-r.Context().Done()
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := NewMemoryCursorStore()
+	policy := ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	got := map[MatchingDirection][]int64{}
+	err := client.StreamMatchesBoth(ctx, "p_123", MatchesFilter{}, store, policy,
+		func(ctx context.Context, direction MatchingDirection, items []MatchItem) error {
+			for _, m := range items {
+				got[direction] = append(got[direction], m.ID)
+			}
+			if len(got[MatchingDirectionOffer]) == 3 && len(got[MatchingDirectionSeek]) == 2 {
+				cancel()
+			}
+			return nil
+		})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	offer, seek := got[MatchingDirectionOffer], got[MatchingDirectionSeek]
+	if len(offer) != 3 || offer[0] != 10 || offer[1] != 11 || offer[2] != 12 {
+		t.Fatalf("unexpected Offer items: %v", offer)
+	}
+	if len(seek) != 2 || seek[0] != 20 || seek[1] != 21 {
+		t.Fatalf("unexpected Seek items: %v", seek)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if c := conns[MatchingDirectionOffer.Query()]; len(c) != 2 || c[0] != "10" || c[1] != "11" {
+		t.Fatalf("expected Offer to reconnect from its own cursor, got sinceId %q", c)
+	}
+	if c := conns[MatchingDirectionSeek.Query()]; len(c) != 1 || c[0] != "20" {
+		t.Fatalf("expected a single uninterrupted Seek connection, got sinceId %q", c)
+	}
+
+	for direction, want := range map[MatchingDirection]int64{MatchingDirectionOffer: 12, MatchingDirectionSeek: 21} {
+		cur, ok, _ := store.LoadCursor(context.Background(), matchesCursorKey("p_123", direction))
+		if !ok || cur.ID != want {
+			t.Fatalf("%s: expected stored cursor %d, got %+v ok=%v", direction, want, cur, ok)
+		}
+	}
+}
+
+// TestStreamMatchesBoth_HandlerError verifies that a handler error stops
+// both directions and is returned without reconnecting.
+func TestStreamMatchesBoth_HandlerError(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var conns int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{ProID: "p_123", CursorUpdatedUTC: t0, CursorID: 1})
+		case "/api/matches/items/stream":
+			atomic.AddInt32(&conns, 1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+				ProID:            "p_123",
+				CursorUpdatedUTC: t0.Add(time.Minute),
+				CursorID:         2,
+				Items:            []MatchItem{{ID: 2, UpdatedUTC: t0.Add(time.Minute)}},
+			})
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errStop := errors.New("stop")
+	calls := 0
+	policy := ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	err := client.StreamMatchesBoth(ctx, "p_123", MatchesFilter{}, NewMemoryCursorStore(), policy,
+		func(ctx context.Context, direction MatchingDirection, items []MatchItem) error {
+			calls++
+			return errStop
+		})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single handler call, got %d", calls)
+	}
+	if n := atomic.LoadInt32(&conns); n > 2 {
+		t.Fatalf("expected no reconnection after the handler error, got %d connections", n)
+	}
+}
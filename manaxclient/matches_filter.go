@@ -0,0 +1,44 @@
+package manaxclient
+
+import (
+	"context"
+	"strings"
+)
+
+// FilterMatchesByTargets returns the items whose TargetProID is one of
+// targets, in their original order. Target ids are compared after
+// trimming surrounding whitespace. With no targets the result is empty.
+//
+// The result is never nil and never aliases items.
+func FilterMatchesByTargets(items []MatchItem, targets ...string) []MatchItem {
+	out := []MatchItem{}
+	if len(targets) == 0 {
+		return out
+	}
+	set := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		set[strings.TrimSpace(t)] = struct{}{}
+	}
+	for _, m := range items {
+		if _, ok := set[m.TargetProID]; ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// FilterMatchesHandler wraps handler so that it only sees matches whose
+// TargetProID is one of targets (see FilterMatchesByTargets).
+//
+// Every chunk is still delivered, with Items filtered, so that the
+// handler can keep advancing and persisting the stream cursor even when
+// no relevant match changed. The chunk passed to handler is a copy; the
+// original is not modified.
+func FilterMatchesHandler(handler MatchesStreamHandler, targets ...string) MatchesStreamHandler {
+	targets = append([]string(nil), targets...)
+	return func(ctx context.Context, chunk *MatchesStreamChunk) error {
+		filtered := *chunk
+		filtered.Items = FilterMatchesByTargets(chunk.Items, targets...)
+		return handler(ctx, &filtered)
+	}
+}
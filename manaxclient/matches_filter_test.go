@@ -0,0 +1,71 @@
+package manaxclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestFilterMatchesByTargets verifies that only the requested targets
+// pass through, in order, and that no targets yields no items.
+func TestFilterMatchesByTargets(t *testing.T) {
+	items := []MatchItem{
+		{ID: 1, TargetProID: "p_a"},
+		{ID: 2, TargetProID: "p_b"},
+		{ID: 3, TargetProID: "p_c"},
+		{ID: 4, TargetProID: "p_a"},
+	}
+
+	got := FilterMatchesByTargets(items, " p_a", "p_c", "p_missing")
+	if len(got) != 3 || got[0].ID != 1 || got[1].ID != 3 || got[2].ID != 4 {
+		t.Fatalf("unexpected filtered items: %#v", got)
+	}
+	if got := FilterMatchesByTargets(items); got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil result without targets, got %#v", got)
+	}
+}
+
+// TestFilterMatchesHandler verifies that a streamed chunk reaches the
+// handler with only relevant matches but with its cursor intact.
+func TestFilterMatchesHandler(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+			CursorID: 7,
+			Items: []MatchItem{
+				{ID: 1, TargetProID: "p_a"},
+				{ID: 2, TargetProID: "p_b"},
+			},
+		})
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+			CursorID: 8,
+			Items:    []MatchItem{{ID: 3, TargetProID: "p_b"}},
+		})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var chunks []MatchesStreamChunk
+	h := FilterMatchesHandler(func(ctx context.Context, chunk *MatchesStreamChunk) error {
+		chunks = append(chunks, *chunk)
+		return nil
+	}, "p_a")
+
+	err := client.StreamMatches(context.Background(), "p_123", MatchesStreamCursor{UpdatedUTC: time.Now(), ID: 1},
+		MatchesStreamOptions{Direction: MatchingDirectionOffer}, h)
+	if err != nil {
+		t.Fatalf("StreamMatches returned error: %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].CursorID != 7 || len(chunks[0].Items) != 1 || chunks[0].Items[0].ID != 1 {
+		t.Fatalf("unexpected first chunk: %#v", chunks[0])
+	}
+	if chunks[1].CursorID != 8 || len(chunks[1].Items) != 0 {
+		t.Fatalf("expected empty second chunk with cursor 8, got %#v", chunks[1])
+	}
+}
@@ -0,0 +1,50 @@
+package manaxclient
+
+// MergeMatchesChunks combines several updates responses, typically
+// batched from consecutive GetMatchesUpdates or stream chunks, into one.
+//
+// The result:
+//   - contains every match ID once, keeping the version with the newest
+//     UpdatedUTC (on ties, the one from the later chunk), at the position
+//     where the ID first appeared;
+//   - carries the greatest (CursorUpdatedUTC, CursorID) of all chunks;
+//   - takes ProID and Direction from the first non-empty chunk.
+//
+// Nil and empty chunks (no items and no cursor) are skipped. The result
+// is never nil; merging no chunks yields an empty response. Input chunks
+// are not modified.
+func MergeMatchesChunks(chunks ...*MatchesUpdatesResponse) *MatchesUpdatesResponse {
+	out := &MatchesUpdatesResponse{Items: []MatchItem{}}
+	index := make(map[int64]int)
+	first := true
+
+	for _, ch := range chunks {
+		if ch == nil || (len(ch.Items) == 0 && ch.CursorUpdatedUTC.IsZero() && ch.CursorID == 0) {
+			continue
+		}
+		if first {
+			out.ProID = ch.ProID
+			out.Direction = ch.Direction
+			first = false
+		}
+
+		cur := Cursor{UpdatedUTC: ch.CursorUpdatedUTC, ID: ch.CursorID}
+		if cur.After(Cursor{UpdatedUTC: out.CursorUpdatedUTC, ID: out.CursorID}) {
+			out.CursorUpdatedUTC = cur.UpdatedUTC
+			out.CursorID = cur.ID
+		}
+
+		for _, m := range ch.Items {
+			i, seen := index[m.ID]
+			if !seen {
+				index[m.ID] = len(out.Items)
+				out.Items = append(out.Items, m)
+				continue
+			}
+			if !m.UpdatedUTC.Before(out.Items[i].UpdatedUTC) {
+				out.Items[i] = m
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package manaxclient
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMergeMatchesChunks verifies deduplication by ID keeping the newest
+// version, maximum cursor computation and nil/empty chunk handling.
+func TestMergeMatchesChunks(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	offer := MatchingDirectionOffer
+
+	a := &MatchesUpdatesResponse{
+		ProID:            "p_123",
+		Direction:        &offer,
+		CursorUpdatedUTC: t0.Add(2 * time.Minute),
+		CursorID:         5,
+		Items: []MatchItem{
+			{ID: 1, Score: 0.1, UpdatedUTC: t0},
+			{ID: 2, Score: 0.2, UpdatedUTC: t0.Add(2 * time.Minute)},
+		},
+	}
+	b := &MatchesUpdatesResponse{
+		ProID:            "p_123",
+		CursorUpdatedUTC: t0.Add(2 * time.Minute),
+		CursorID:         7,
+		Items: []MatchItem{
+			{ID: 1, Score: 0.9, UpdatedUTC: t0.Add(time.Minute)},
+			{ID: 2, Score: 0.0, UpdatedUTC: t0}, // older: must not win
+			{ID: 3, Score: 0.3, UpdatedUTC: t0.Add(time.Minute)},
+		},
+	}
+	c := &MatchesUpdatesResponse{CursorUpdatedUTC: t0, CursorID: 99}
+
+	got := MergeMatchesChunks(nil, a, &MatchesUpdatesResponse{}, b, c, nil)
+
+	if got.ProID != "p_123" || got.Direction == nil || *got.Direction != offer {
+		t.Fatalf("unexpected header fields: %#v", got)
+	}
+	if !got.CursorUpdatedUTC.Equal(t0.Add(2*time.Minute)) || got.CursorID != 7 {
+		t.Fatalf("expected max cursor (t0+2m, 7), got (%v, %d)", got.CursorUpdatedUTC, got.CursorID)
+	}
+
+	wantScores := map[int64]float64{1: 0.9, 2: 0.2, 3: 0.3}
+	if len(got.Items) != 3 {
+		t.Fatalf("expected 3 deduplicated items, got %#v", got.Items)
+	}
+	for i, m := range got.Items {
+		if m.ID != int64(i+1) || m.Score != wantScores[m.ID] {
+			t.Fatalf("unexpected item %d: %#v", i, m)
+		}
+	}
+
+	if empty := MergeMatchesChunks(); empty == nil || len(empty.Items) != 0 || empty.CursorID != 0 {
+		t.Fatalf("expected empty non-nil result, got %#v", empty)
+	}
+}
+
+// TestMergeMatchesChunks_EmptyLeadingChunk verifies that an empty first
+// chunk does not provide ProID and Direction.
+func TestMergeMatchesChunks_EmptyLeadingChunk(t *testing.T) {
+	offer, seek := MatchingDirectionOffer, MatchingDirectionSeek
+	empty := &MatchesUpdatesResponse{ProID: "p_other", Direction: &seek}
+	chunk := &MatchesUpdatesResponse{
+		ProID:     "p_123",
+		Direction: &offer,
+		CursorID:  1,
+		Items:     []MatchItem{{ID: 1}},
+	}
+
+	got := MergeMatchesChunks(empty, chunk)
+	if got.ProID != "p_123" || got.Direction == nil || *got.Direction != offer {
+		t.Fatalf("expected header fields of the non-empty chunk, got %#v", got)
+	}
+}
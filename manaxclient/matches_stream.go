@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"strconv"
 	"time"
@@ -20,14 +18,9 @@ import (
 //   2. Pass these values as sinceUpdatedUtc / sinceId when opening the
 //      SSE stream.
 //   3. For each SSE update chunk, update the cursor and persist it.
-type MatchesStreamCursor struct {
-	// UpdatedUTC is the last seen CursorUpdatedUtc from either a snapshot
-	// or a previous updates chunk.
-	UpdatedUTC time.Time
-
-	// ID is the last seen CursorId associated with UpdatedUTC.
-	ID int64
-}
+//
+// It is an alias of Cursor, the watermark shared by all incremental APIs.
+type MatchesStreamCursor = Cursor
 
 // MatchesStreamOptions configures additional filters for the matches
 // SSE stream. These fields directly map to the MatchingController
@@ -46,7 +39,8 @@ type MatchesStreamOptions struct {
 
 	// Limit is the maximum number of items returned per update chunk.
 	// The server enforces bounds and defaults (e.g. 500).
-	// Use 0 to let the server choose the default.
+	// Use 0 to let the server choose the default, LimitAll for its
+	// maximum.
 	Limit int
 
 	// MinRationaleLength and MaxRationaleLength restrict the length
@@ -54,6 +48,26 @@ type MatchesStreamOptions struct {
 	// server side; 0 means "no bound".
 	MinRationaleLength int
 	MaxRationaleLength int
+
+	// StreamOptions carries behaviour shared with the other streams,
+	// such as id-based deduplication.
+	StreamOptions
+
+	// Reconnect, when non-nil, makes the stream reopen after the server
+	// closes it or a transient error occurs, until ctx is cancelled, the
+	// handler fails or the policy gives up. Every reconnection resumes
+	// from the cursor of the last chunk handled successfully and sends
+	// the last event id as Last-Event-ID. The delay is the server's
+	// "retry:" value when one was received, the policy's backoff
+	// otherwise; each reconnection consumes one unit of the ctx retry
+	// budget, if any (see WithRetryBudget).
+	Reconnect *ReconnectPolicy
+
+	// OnReconnect, if set, is called before every reconnection with the
+	// attempt number, the error that ended the previous connection (nil
+	// after a clean close) and the delay about to be waited. It is only
+	// used with Reconnect.
+	OnReconnect func(RetryEvent)
 }
 
 // MatchesStreamChunk is just an alias of MatchesUpdatesResponse:
@@ -77,6 +91,11 @@ type MatchesStreamChunk = MatchesUpdatesResponse
 // is propagated back to the caller of StreamMatches.
 type MatchesStreamHandler func(ctx context.Context, chunk *MatchesStreamChunk) error
 
+// MatchesStreamMetaHandler is the StreamMatchesWithMeta counterpart of
+// MatchesStreamHandler: it additionally receives the metadata of the SSE
+// event the chunk was decoded from.
+type MatchesStreamMetaHandler func(ctx context.Context, chunk *MatchesStreamChunk, meta SSEEventMeta) error
+
 // StreamMatches establishes an SSE connection to
 //   GET /api/matches/items/stream
 //
@@ -110,6 +129,8 @@ type MatchesStreamHandler func(ctx context.Context, chunk *MatchesStreamChunk) e
 //       * EOF from the server;
 //       * any I/O or JSON decoding error;
 //       * non-nil error from the handler.
+//     With opt.Reconnect set, EOF and transient errors reopen the stream
+//     from the last handled chunk's cursor instead.
 //
 // The caller is responsible for:
 //   - Obtaining an initial MatchesItemsResponse from GetMatchesSnapshot,
@@ -123,124 +144,129 @@ func (c *Client) StreamMatches(
 	opt MatchesStreamOptions,
 	handler MatchesStreamHandler,
 ) error {
-	proID = strings.TrimSpace(proID)
-	if proID == "" {
-		return errors.New("StreamMatches: proID must not be empty")
-	}
 	if handler == nil {
 		return errors.New("StreamMatches: handler must not be nil")
 	}
+	return c.streamMatches(ctx, "StreamMatches", proID, cursor, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk, _ SSEEventMeta) error {
+			return handler(ctx, chunk)
+		})
+}
+
+// StreamMatchesWithMeta behaves like StreamMatches but passes the SSE
+// event metadata (id, retry, preceding comments) alongside every decoded
+// chunk. Use it when logging or cursor persistence is keyed by event id;
+// otherwise prefer the simpler StreamMatches.
+func (c *Client) StreamMatchesWithMeta(
+	ctx context.Context,
+	proID string,
+	cursor MatchesStreamCursor,
+	opt MatchesStreamOptions,
+	handler MatchesStreamMetaHandler,
+) error {
+	if handler == nil {
+		return errors.New("StreamMatchesWithMeta: handler must not be nil")
+	}
+	opt.keepComments = true
+	return c.streamMatches(ctx, "StreamMatchesWithMeta", proID, cursor, opt, handler)
+}
+
+// streamMatches implements StreamMatches and StreamMatchesWithMeta; op is
+// the public method name used in error messages.
+func (c *Client) streamMatches(
+	ctx context.Context,
+	op string,
+	proID string,
+	cursor MatchesStreamCursor,
+	opt MatchesStreamOptions,
+	handler MatchesStreamMetaHandler,
+) error {
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return fmt.Errorf("%s: proID must not be empty", op)
+	}
 	if opt.Direction == "" {
-		return errors.New("StreamMatches: Direction must not be empty")
+		return fmt.Errorf("%s: Direction must not be empty", op)
 	}
 	if cursor.ID < 0 {
-		return errors.New("StreamMatches: cursor.ID must be >= 0")
+		return fmt.Errorf("%s: cursor.ID must be >= 0", op)
+	}
+
+	if cursor.UpdatedUTC.IsZero() {
+		return fmt.Errorf("%s: cursor.UpdatedUTC must not be zero", op)
 	}
 
-	// Build query string.
+	var r streamReconnect
+	var lastEventID string
+	s := sseStream{
+		op:       op,
+		endpoint: "/api/matches/items/stream",
+		handlers: map[string]EventHandler{
+			"matches": func(ctx context.Context, ev *SSEEvent, meta SSEEventMeta) error {
+				var chunk MatchesStreamChunk
+				if err := json.Unmarshal(ev.Data, &chunk); err != nil {
+					r.stopErr = fmt.Errorf("%s: decode JSON payload: %w", op, err)
+					return r.stopErr
+				}
+				if err := c.checkProID(op, proID, chunk.ProID); err != nil {
+					r.stopErr = err
+					return err
+				}
+				if err := handler(ctx, &chunk, meta); err != nil {
+					r.stopErr = err
+					return err
+				}
+				next := Cursor{UpdatedUTC: chunk.CursorUpdatedUTC, ID: chunk.CursorID}
+				if next.After(cursor) {
+					cursor = next
+				}
+				if opt.LastCursor != nil && !next.UpdatedUTC.IsZero() {
+					*opt.LastCursor = next
+				}
+				if meta.ID != "" {
+					lastEventID = meta.ID
+				}
+				r.handled++
+				return nil
+			},
+		},
+		defaultEvent: "matches",
+		opt:          opt.StreamOptions,
+	}
+	if opt.Reconnect == nil {
+		s.query = matchesStreamQuery(proID, cursor, opt)
+		return c.runStream(ctx, s)
+	}
+
+	r.prepare = func(s *sseStream) {
+		s.query = matchesStreamQuery(proID, cursor, opt)
+		s.lastEventID = lastEventID
+	}
+	r.onReconnect = opt.OnReconnect
+	return c.runStreamReconnecting(ctx, s, *opt.Reconnect, &r)
+}
+
+// matchesStreamQuery builds the query of a matches stream connection
+// resuming from cursor.
+func matchesStreamQuery(proID string, cursor MatchesStreamCursor, opt MatchesStreamOptions) url.Values {
 	q := url.Values{}
 	q.Set("proId", proID)
 
 	// The server normalizes kind to UTC; we ensure it is formatted as RFC3339.
-	if cursor.UpdatedUTC.IsZero() {
-		return errors.New("StreamMatches: cursor.UpdatedUTC must not be zero")
-	}
 	q.Set("sinceUpdatedUtc", cursor.UpdatedUTC.UTC().Format(time.RFC3339))
 	q.Set("sinceId", strconv.FormatInt(cursor.ID, 10))
 
-	q.Set("direction", string(opt.Direction))
+	q.Set("direction", opt.Direction.Query())
 
 	if opt.MinScore > 0 {
 		q.Set("minScore", strconv.FormatFloat(opt.MinScore, 'f', -1, 64))
 	}
-	if opt.Limit > 0 {
-		q.Set("limit", strconv.Itoa(opt.Limit))
-	}
+	setLimit(q, opt.Limit)
 	if opt.MinRationaleLength > 0 {
 		q.Set("minRationaleLength", strconv.Itoa(opt.MinRationaleLength))
 	}
 	if opt.MaxRationaleLength > 0 {
 		q.Set("maxRationaleLength", strconv.Itoa(opt.MaxRationaleLength))
 	}
-
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/matches/items/stream", q, nil)
-	if err != nil {
-		return fmt.Errorf("StreamMatches: create request: %w", err)
-	}
-
-	h := http.Header{}
-	h.Set("Accept", "text/event-stream")
-	c.applyHeaders(req, h)
-
-	resp, err := c.HTTPClient().Do(req)
-	if err != nil {
-		if ctxErr := ctx.Err(); ctxErr != nil {
-			return ctxErr
-		}
-		return fmt.Errorf("StreamMatches: http request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
-
-		var payload struct {
-			Error string `json:"error"`
-		}
-		_ = json.Unmarshal(data, &payload)
-
-		msg := strings.TrimSpace(payload.Error)
-		if msg == "" && len(data) > 0 {
-			msg = strings.TrimSpace(string(data))
-		}
-		if msg == "" {
-			msg = resp.Status
-		}
-
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    msg,
-			Body:       data,
-		}
-	}
-
-	reader := newSSEReader(resp.Body)
-
-	for {
-		ev, err := reader.ReadEvent()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				return nil
-			}
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return ctxErr
-			}
-			return fmt.Errorf("StreamMatches: read SSE event: %w", err)
-		}
-		if ev == nil {
-			continue
-		}
-
-		// Ignore comments (: matches-stream-start / : idle / : matches-stream-end).
-		if ev.Comment != "" && ev.Event == "" && len(ev.Data) == 0 {
-			continue
-		}
-
-		if ev.Event != "" && ev.Event != "matches" {
-			continue
-		}
-
-		if len(ev.Data) == 0 {
-			return fmt.Errorf("StreamMatches: received event \"matches\" with empty data payload")
-		}
-
-		var chunk MatchesStreamChunk
-		if err := json.Unmarshal(ev.Data, &chunk); err != nil {
-			return fmt.Errorf("StreamMatches: decode JSON payload: %w", err)
-		}
-
-		if err := handler(ctx, &chunk); err != nil {
-			return err
-		}
-	}
-}
\ No newline at end of file
+	return q
+}
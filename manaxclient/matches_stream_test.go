@@ -3,8 +3,11 @@ package manaxclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -118,4 +121,246 @@ func TestStreamMatches_Basic(t *testing.T) {
 // for tests without repeating address-of syntax.
 func ptrDirection(d MatchingDirection) *MatchingDirection {
 	return &d
-}
\ No newline at end of file
+}
+
+// TestStreamMatchesWithMeta verifies that the metadata handler receives
+// the event id, retry value and preceding comments of each event.
+func TestStreamMatchesWithMeta(t *testing.T) {
+	now := time.Now().UTC()
+
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": matches-stream-start\n\n"))
+		w.Write([]byte(": idle\n\n"))
+		w.Write([]byte("id: 41\nretry: 3000\nevent: matches\ndata: {\"cursorId\":41}\n\n"))
+		w.Write([]byte("id: 42\nevent: matches\ndata: {\"cursorId\":42}\n\n"))
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var metas []SSEEventMeta
+	cursor := MatchesStreamCursor{UpdatedUTC: now, ID: 5}
+	opt := MatchesStreamOptions{Direction: MatchingDirectionOffer}
+
+	err := client.StreamMatchesWithMeta(context.Background(), "p_123", cursor, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk, meta SSEEventMeta) error {
+			if meta.ID != strconv.FormatInt(chunk.CursorID, 10) {
+				t.Fatalf("meta.ID %q does not match chunk cursor %d", meta.ID, chunk.CursorID)
+			}
+			metas = append(metas, meta)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("StreamMatchesWithMeta returned error: %v", err)
+	}
+
+	if len(metas) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(metas))
+	}
+	if metas[0].ID != "41" || metas[0].Retry != "3000" {
+		t.Fatalf("unexpected first meta: %#v", metas[0])
+	}
+	if len(metas[0].Comments) != 2 || metas[0].Comments[0] != "matches-stream-start" || metas[0].Comments[1] != "idle" {
+		t.Fatalf("unexpected first meta comments: %#v", metas[0].Comments)
+	}
+	if metas[1].ID != "42" || metas[1].Retry != "" || len(metas[1].Comments) != 0 {
+		t.Fatalf("unexpected second meta: %#v", metas[1])
+	}
+}
+
+// TestStreamMatchesWithMeta_CommentsBounded verifies that only the latest
+// maxMetaComments keepalives are kept, and that handlers which do not
+// read metadata get none buffered.
+func TestStreamMatchesWithMeta_CommentsBounded(t *testing.T) {
+	now := time.Now().UTC()
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(w, ": ka %d\n\n", i)
+		}
+		w.Write([]byte("id: 1\nevent: matches\ndata: {\"cursorId\":1}\n\n"))
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	cursor := MatchesStreamCursor{UpdatedUTC: now, ID: 0}
+	opt := MatchesStreamOptions{Direction: MatchingDirectionOffer}
+
+	var comments []string
+	err := client.StreamMatchesWithMeta(context.Background(), "p_123", cursor, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk, meta SSEEventMeta) error {
+			comments = meta.Comments
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("StreamMatchesWithMeta returned error: %v", err)
+	}
+	if len(comments) != maxMetaComments || comments[0] != "ka 36" || comments[len(comments)-1] != "ka 99" {
+		t.Fatalf("expected the latest %d comments, got %d: %q ... %q",
+			maxMetaComments, len(comments), comments[0], comments[len(comments)-1])
+	}
+
+	// The plain StreamMatches path does not collect comments at all.
+	err = client.streamMatches(context.Background(), "StreamMatches", "p_123", cursor, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk, meta SSEEventMeta) error {
+			if meta.Comments != nil {
+				t.Errorf("expected no buffered comments, got %d", len(meta.Comments))
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("streamMatches returned error: %v", err)
+	}
+}
+
+// TestStreamMatches_LastCursor verifies that LastCursor survives a decode
+// error with the cursor of the last delivered chunk, and that a chunk
+// without a cursor does not clear it.
+func TestStreamMatches_LastCursor(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorUpdatedUTC: t0.Add(time.Minute), CursorID: 5})
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorUpdatedUTC: t0.Add(2 * time.Minute), CursorID: 9})
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{})
+		_, _ = w.Write([]byte("event: matches\ndata: {broken\n\n"))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var last Cursor
+	opt := MatchesStreamOptions{Direction: MatchingDirectionOffer}
+	opt.LastCursor = &last
+	err := client.StreamMatches(context.Background(), "p_123", Cursor{UpdatedUTC: t0, ID: 1}, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "decode JSON payload") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if last.ID != 9 || !last.UpdatedUTC.Equal(t0.Add(2*time.Minute)) {
+		t.Fatalf("expected cursor of the second chunk, got %+v", last)
+	}
+}
+
+// TestStreamMatches_Reconnect verifies that a closed stream is reopened
+// from the last chunk's cursor and event id, after the server's retry
+// delay, and that the policy eventually gives up.
+func TestStreamMatches_Reconnect(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var queries, lastIDs []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("sinceId"))
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		if len(queries) == 1 {
+			_, _ = w.Write([]byte("retry: 0\n"))
+			writeSSEEventWithID(t, w, "matches", "e7", MatchesStreamChunk{CursorUpdatedUTC: t0.Add(time.Minute), CursorID: 7})
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var events []RetryEvent
+	opt := MatchesStreamOptions{
+		Direction: MatchingDirectionOffer,
+		Reconnect: &ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		OnReconnect: func(ev RetryEvent) {
+			events = append(events, ev)
+		},
+	}
+	chunks := 0
+	err := client.StreamMatches(context.Background(), "p_123", Cursor{UpdatedUTC: t0, ID: 1}, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk) error {
+			chunks++
+			return nil
+		})
+	if err == nil || !strings.Contains(err.Error(), "giving up after 2 reconnect attempts") {
+		t.Fatalf("expected the policy to give up, got %v", err)
+	}
+	if chunks != 1 {
+		t.Fatalf("expected 1 chunk, got %d", chunks)
+	}
+	if len(queries) != 3 || queries[0] != "1" || queries[1] != "7" || queries[2] != "7" {
+		t.Fatalf("expected reconnections from cursor 7, got sinceId %q", queries)
+	}
+	if lastIDs[0] != "" || lastIDs[1] != "e7" || lastIDs[2] != "e7" {
+		t.Fatalf("unexpected Last-Event-ID headers: %q", lastIDs)
+	}
+	if len(events) != 2 || events[0].Attempt != 1 || events[1].Attempt != 2 || events[0].Delay != 0 {
+		t.Fatalf("unexpected reconnect events: %+v", events)
+	}
+}
+
+// TestStreamMatches_ReconnectRetryEvent verifies that a standalone
+// "retry:" event is not dispatched and sets the reconnection delay.
+func TestStreamMatches_ReconnectRetryEvent(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	conns := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conns++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if conns == 1 {
+			_, _ = w.Write([]byte("retry: 20\n\n"))
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorUpdatedUTC: t0.Add(time.Minute), CursorID: 7})
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var delays []time.Duration
+	opt := MatchesStreamOptions{
+		Direction:   MatchingDirectionOffer,
+		Reconnect:   &ReconnectPolicy{MaxAttempts: 1, InitialBackoff: time.Hour},
+		OnReconnect: func(ev RetryEvent) { delays = append(delays, ev.Delay) },
+	}
+	chunks := 0
+	err := client.StreamMatches(context.Background(), "p_123", Cursor{UpdatedUTC: t0, ID: 1}, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk) error {
+			chunks++
+			return nil
+		})
+	if err == nil || !strings.Contains(err.Error(), "giving up after 1 reconnect attempts") {
+		t.Fatalf("expected the policy to give up, got %v", err)
+	}
+	if chunks != 1 || conns != 2 {
+		t.Fatalf("expected 1 chunk over 2 connections, got %d chunks, %d connections", chunks, conns)
+	}
+	if len(delays) != 1 || delays[0] != 20*time.Millisecond {
+		t.Fatalf("expected the server retry delay, got %v", delays)
+	}
+}
+
+// TestStreamMatches_ReconnectCancel verifies that cancelling the context
+// while waiting to reconnect ends the stream at once.
+func TestStreamMatches_ReconnectCancel(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opt := MatchesStreamOptions{
+		Direction:   MatchingDirectionOffer,
+		Reconnect:   &ReconnectPolicy{InitialBackoff: time.Hour},
+		OnReconnect: func(RetryEvent) { cancel() },
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.StreamMatches(ctx, "p_123", Cursor{UpdatedUTC: time.Now(), ID: 1}, opt,
+			func(ctx context.Context, chunk *MatchesStreamChunk) error { return nil })
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("StreamMatches did not return after cancellation")
+	}
+}
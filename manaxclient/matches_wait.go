@@ -0,0 +1,212 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitPollInterval is the delay used by WaitForMatch between two
+// GetMatchesUpdates calls in poll mode, and between stream reconnections
+// in stream mode, when PollOrStreamOptions.PollInterval is not set.
+const DefaultWaitPollInterval = 2 * time.Second
+
+// errStopStream is returned internally by stream handlers to terminate
+// a stream once the caller has obtained what it was waiting for. It is
+// never surfaced to users of the public API.
+var errStopStream = errors.New("manaxclient: stop stream")
+
+// MatchesFilter groups the optional server-side filters shared by the
+// matches snapshot, updates and stream endpoints. Zero values disable
+// the corresponding filter and let the server apply its defaults.
+type MatchesFilter struct {
+	// MinScore is an optional lower bound for the match score.
+	MinScore float64
+
+	// Limit is the maximum number of items per response (server-clamped);
+	// 0 omits it and LimitAll requests the server maximum.
+	Limit int
+
+	// MinRationaleLength and MaxRationaleLength restrict the length of
+	// the rationale text; 0 means "no bound".
+	MinRationaleLength int
+	MaxRationaleLength int
+}
+
+// streamOptions returns the MatchesStreamOptions applying f to direction.
+func (f MatchesFilter) streamOptions(direction MatchingDirection) MatchesStreamOptions {
+	return MatchesStreamOptions{
+		Direction:          direction,
+		MinScore:           f.MinScore,
+		Limit:              f.Limit,
+		MinRationaleLength: f.MinRationaleLength,
+		MaxRationaleLength: f.MaxRationaleLength,
+	}
+}
+
+// PollOrStreamOptions configures how WaitForMatch observes new matches.
+type PollOrStreamOptions struct {
+	// MatchesFilter is applied to the initial snapshot and to every
+	// subsequent stream or poll request.
+	MatchesFilter
+
+	// Poll switches WaitForMatch from the SSE stream to periodic
+	// GetMatchesUpdates calls. Use it when streaming is unavailable,
+	// for example behind a buffering proxy.
+	Poll bool
+
+	// PollInterval is the delay between two updates calls in poll mode
+	// and between reconnections in stream mode. If <= 0,
+	// DefaultWaitPollInterval is used.
+	PollInterval time.Duration
+}
+
+// WaitForMatch blocks until a match satisfying predicate appears for the
+// given proID and direction, and returns a copy of the first such item.
+//
+// The method:
+//  1. Fetches a snapshot via GetMatchesSnapshot and checks its items.
+//  2. Follows changes from the snapshot cursor, either via StreamMatches
+//     (default) or by polling GetMatchesUpdates (opt.Poll).
+//  3. Returns as soon as an item matches, closing the stream cleanly.
+//
+// If the server closes the stream before a match is found, WaitForMatch
+// reconnects from the last observed cursor after opt.PollInterval; each
+// reconnection consumes one unit of the ctx retry budget, if any.
+// It returns ctx.Err() when the context is cancelled or expires first.
+func (c *Client) WaitForMatch(
+	ctx context.Context,
+	proID string,
+	direction MatchingDirection,
+	predicate func(MatchItem) bool,
+	opt PollOrStreamOptions,
+) (*MatchItem, error) {
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return nil, errors.New("WaitForMatch: proID must not be empty")
+	}
+	if direction == "" {
+		return nil, errors.New("WaitForMatch: direction must not be empty")
+	}
+	if predicate == nil {
+		return nil, errors.New("WaitForMatch: predicate must not be nil")
+	}
+
+	interval := opt.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+
+	snap, err := c.GetMatchesSnapshot(
+		ctx,
+		proID,
+		direction,
+		opt.MinScore,
+		opt.Limit,
+		opt.MinRationaleLength,
+		opt.MaxRationaleLength,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if m := firstMatch(snap.Items, predicate); m != nil {
+		return m, nil
+	}
+
+	cursor := MatchesStreamCursor{
+		UpdatedUTC: snap.CursorUpdatedUTC,
+		ID:         snap.CursorID,
+	}
+	if cursor.UpdatedUTC.IsZero() {
+		// An empty snapshot carries no cursor; the stream endpoint still
+		// requires one, so start from the beginning of time.
+		cursor.UpdatedUTC = time.Unix(0, 0).UTC()
+	}
+
+	if opt.Poll {
+		return c.waitForMatchPoll(ctx, proID, direction, cursor, predicate, opt.MatchesFilter, interval)
+	}
+	return c.waitForMatchStream(ctx, proID, direction, cursor, predicate, opt.MatchesFilter, interval)
+}
+
+// waitForMatchStream implements the stream mode of WaitForMatch.
+func (c *Client) waitForMatchStream(
+	ctx context.Context,
+	proID string,
+	direction MatchingDirection,
+	cursor MatchesStreamCursor,
+	predicate func(MatchItem) bool,
+	filter MatchesFilter,
+	interval time.Duration,
+) (*MatchItem, error) {
+	streamOpt := filter.streamOptions(direction)
+
+	for attempt := 1; ; attempt++ {
+		var found *MatchItem
+		err := c.StreamMatches(ctx, proID, cursor, streamOpt, func(ctx context.Context, chunk *MatchesStreamChunk) error {
+			if m := firstMatch(chunk.Items, predicate); m != nil {
+				found = m
+				return errStopStream
+			}
+			if !chunk.CursorUpdatedUTC.IsZero() {
+				cursor.UpdatedUTC = chunk.CursorUpdatedUTC
+				cursor.ID = chunk.CursorID
+			}
+			return nil
+		})
+		if found != nil {
+			return found, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The server closed the stream; reconnect from the last cursor.
+		if err := consumeRetry(ctx); err != nil {
+			return nil, fmt.Errorf("WaitForMatch: reconnect: %w", err)
+		}
+		c.reportRetry(newRetryEvent("/api/matches/items/stream", attempt, nil, interval))
+		if err := waitOrCancel(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForMatchPoll implements the poll mode of WaitForMatch on top of
+// PollMatches with a fixed interval.
+func (c *Client) waitForMatchPoll(
+	ctx context.Context,
+	proID string,
+	direction MatchingDirection,
+	cursor MatchesStreamCursor,
+	predicate func(MatchItem) bool,
+	filter MatchesFilter,
+	interval time.Duration,
+) (*MatchItem, error) {
+	var found *MatchItem
+	pollOpt := PollOptions{MinInterval: interval, MaxInterval: interval}
+	err := c.PollMatches(ctx, proID, direction, cursor, filter, pollOpt, func(ctx context.Context, upd *MatchesUpdatesResponse) error {
+		if m := firstMatch(upd.Items, predicate); m != nil {
+			found = m
+			return errStopStream
+		}
+		return nil
+	})
+	if found != nil {
+		return found, nil
+	}
+	return nil, err
+}
+
+// firstMatch returns a copy of the first item satisfying predicate,
+// or nil if none does.
+func firstMatch(items []MatchItem, predicate func(MatchItem) bool) *MatchItem {
+	for i := range items {
+		if predicate(items[i]) {
+			m := items[i]
+			return &m
+		}
+	}
+	return nil
+}
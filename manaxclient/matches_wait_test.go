@@ -0,0 +1,152 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestWaitForMatch_Stream verifies that WaitForMatch bootstraps from the
+// snapshot, follows the stream from the snapshot cursor and returns the
+// first item satisfying the predicate, which arrives in the second chunk.
+func TestWaitForMatch_Stream(t *testing.T) {
+	now := time.Now().UTC()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{
+				ProID:            "p_123",
+				Direction:        MatchingDirectionOffer,
+				CursorUpdatedUTC: now,
+				CursorID:         7,
+				Items: []MatchItem{
+					{ID: 1, TargetProID: "p_low", Score: 0.1},
+				},
+			})
+		case "/api/matches/items/stream":
+			if got := r.URL.Query().Get("sinceId"); got != "7" {
+				t.Fatalf("expected stream to start from snapshot cursor, got sinceId=%q", got)
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte(": matches-stream-start\n\n"))
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+				ProID:            "p_123",
+				CursorUpdatedUTC: now.Add(time.Second),
+				CursorID:         8,
+				Items:            []MatchItem{{ID: 8, TargetProID: "p_mid", Score: 0.5}},
+			})
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{
+				ProID:            "p_123",
+				CursorUpdatedUTC: now.Add(2 * time.Second),
+				CursorID:         9,
+				Items:            []MatchItem{{ID: 9, TargetProID: "p_high", Score: 0.95}},
+			})
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := client.WaitForMatch(ctx, "p_123", MatchingDirectionOffer, func(m MatchItem) bool {
+		return m.Score >= 0.9
+	}, PollOrStreamOptions{})
+	if err != nil {
+		t.Fatalf("WaitForMatch returned error: %v", err)
+	}
+	if m.ID != 9 || m.TargetProID != "p_high" {
+		t.Fatalf("unexpected match: %#v", m)
+	}
+}
+
+// TestWaitForMatch_Poll verifies poll mode: the cursor advances between
+// GetMatchesUpdates calls and the match from the second response is returned.
+func TestWaitForMatch_Poll(t *testing.T) {
+	now := time.Now().UTC()
+	polls := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			_ = json.NewEncoder(w).Encode(MatchesItemsResponse{
+				ProID:            "p_123",
+				CursorUpdatedUTC: now,
+				CursorID:         7,
+			})
+		case "/api/matches/items/updates":
+			polls++
+			q := r.URL.Query()
+			switch polls {
+			case 1:
+				if q.Get("sinceId") != "7" {
+					t.Fatalf("unexpected sinceId on first poll: %v", q)
+				}
+				_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{
+					ProID:            "p_123",
+					CursorUpdatedUTC: now.Add(time.Second),
+					CursorID:         8,
+					Items:            []MatchItem{{ID: 8, Score: 0.2}},
+				})
+			default:
+				if q.Get("sinceId") != "8" {
+					t.Fatalf("unexpected sinceId on second poll: %v", q)
+				}
+				_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{
+					ProID:            "p_123",
+					CursorUpdatedUTC: now.Add(2 * time.Second),
+					CursorID:         9,
+					Items:            []MatchItem{{ID: 9, Score: 0.9}},
+				})
+			}
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := client.WaitForMatch(ctx, "p_123", MatchingDirectionOffer, func(m MatchItem) bool {
+		return m.Score >= 0.9
+	}, PollOrStreamOptions{Poll: true, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForMatch returned error: %v", err)
+	}
+	if m.ID != 9 || polls != 2 {
+		t.Fatalf("unexpected result: match=%#v polls=%d", m, polls)
+	}
+}
+
+// TestWaitForMatch_ContextExpires ensures WaitForMatch returns the context
+// error when no item ever satisfies the predicate.
+func TestWaitForMatch_ContextExpires(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123"})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForMatch(ctx, "p_123", MatchingDirectionOffer, func(MatchItem) bool {
+		return false
+	}, PollOrStreamOptions{Poll: true, PollInterval: 5 * time.Millisecond})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package manaxclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc sends a request and returns its response, like
+// http.RoundTripper.RoundTrip. It is the next step of a Middleware chain.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of a request: it may inspect or modify req
+// (adding a request id, starting a trace span, ...), must call next to
+// send it, unless it answers itself, and may inspect the response or
+// error. It must not return a nil response with a nil error.
+type Middleware func(req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+// WithMiddleware appends middlewares to the chain wrapping every request
+// of the client: non-streaming calls, streams (every connection),
+// ProbeStreaming, Warmup and connectivity checks. Middlewares run in
+// registration order, the first one outermost, once the request is fully
+// built, so they observe the final X-Pro-Id and X-Pro-Token headers.
+// Retries (see WithRetry) go through the whole chain again.
+//
+// An error returned by a middleware is propagated as is; the streaming
+// methods prefix it with their name like any other error. Errors of the
+// underlying HTTP client reach middlewares as *TransportError.
+//
+// Middlewares compose with any *http.Client or transport option, and must
+// be safe for concurrent use.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) error {
+		for i, mw := range middlewares {
+			if mw == nil {
+				return fmt.Errorf("WithMiddleware: middleware %d is nil", i)
+			}
+		}
+		c.middlewares = append(c.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// errNoResponse is returned when a middleware returns neither a response
+// nor an error.
+var errNoResponse = errors.New("manaxclient: middleware returned no response")
+
+// send sends req through the middleware chain. The innermost step signs
+// the request (see WithRequestSigner) and sends it with the HTTP client,
+// returning its errors as *TransportError.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	next := c.roundTrip
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		mw, inner := c.middlewares[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return mw(req, inner)
+		}
+	}
+
+	resp, err := next(req)
+	if err == nil && resp == nil {
+		return nil, errNoResponse
+	}
+	return resp, err
+}
+
+// roundTrip is the last step of send.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	if err := c.sign(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := c.HTTPClient().Do(req)
+	if err != nil {
+		return nil, &TransportError{Err: err, Elapsed: time.Since(start)}
+	}
+	return resp, nil
+}
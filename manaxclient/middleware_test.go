@@ -0,0 +1,88 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestWithMiddleware verifies the order of the chain, that middlewares
+// see the final auth headers on plain and streaming requests, that their
+// changes reach the server, and that their errors propagate unchanged.
+func TestWithMiddleware(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-Id") != "req-1" {
+			t.Errorf("%s: missing X-Request-Id", r.URL.Path)
+		}
+		if r.URL.Path == "/api/facts/items/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"found":true}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, s)
+	}
+	outer := func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		record("outer " + req.Header.Get("X-Pro-Id") + " " + req.Header.Get("X-Pro-Token"))
+		req.Header.Set("X-Request-Id", "req-1")
+		resp, err := next(req)
+		record("outer done")
+		return resp, err
+	}
+	inner := func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		record("inner " + req.URL.Path)
+		return next(req)
+	}
+	client := newClientWithOptions(t, server.URL, WithMiddleware(outer, inner))
+	client.SetAuth("p_123", "tok")
+
+	ctx := context.Background()
+	if _, err := client.GetSpeechStatusByID(ctx, 1); err != nil {
+		t.Fatalf("GetSpeechStatusByID failed: %v", err)
+	}
+	if err := client.StreamFacts(ctx, "p_123", func(ctx context.Context, chunk *FactsStreamChunk) error { return nil }); err != nil {
+		t.Fatalf("StreamFacts failed: %v", err)
+	}
+	want := []string{
+		"outer p_123 tok", "inner /api/speech/status", "outer done",
+		"outer p_123 tok", "inner /api/facts/items/stream", "outer done",
+	}
+	if len(trace) != len(want) {
+		t.Fatalf("unexpected trace: %q", trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("unexpected trace: %q", trace)
+		}
+	}
+
+	boom := errors.New("blocked by policy")
+	client = newClientWithOptions(t, server.URL, WithMiddleware(func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return nil, boom
+	}))
+	_, err := client.GetSpeechStatusByID(ctx, 1)
+	var transportErr *TransportError
+	if err != boom || errors.As(err, &transportErr) {
+		t.Fatalf("expected the middleware error unchanged, got %#v", err)
+	}
+	err = client.StreamFacts(ctx, "p_123", func(ctx context.Context, chunk *FactsStreamChunk) error { return nil })
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the stream to fail with the middleware error, got %v", err)
+	}
+}
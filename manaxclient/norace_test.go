@@ -0,0 +1,5 @@
+//go:build !race
+
+package manaxclient
+
+const raceEnabled = false
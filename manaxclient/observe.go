@@ -0,0 +1,76 @@
+package manaxclient
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Logger receives diagnostic messages from the client. *log.Logger
+// satisfies it. Implementations must be safe for concurrent use.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// Metrics receives structured client events for export to a metrics
+// backend. Implementations must be safe for concurrent use and should
+// return quickly: they are called synchronously on the request path.
+type Metrics interface {
+	// ObserveRetry is called once per retry attempt, right before the
+	// client waits ev.Delay and retries.
+	ObserveRetry(ev RetryEvent)
+}
+
+// RetryEvent describes one retry attempt.
+type RetryEvent struct {
+	// Endpoint is the relative API path being retried, e.g.
+	// "/api/matches/items/stream".
+	Endpoint string
+
+	// Attempt is the 1-based number of the retry about to be made.
+	Attempt int
+
+	// StatusCode is the HTTP status that triggered the retry, or 0 when
+	// it was triggered by a transport error or a clean server close.
+	StatusCode int
+
+	// Err is the error that triggered the retry; nil when the server
+	// closed a stream cleanly.
+	Err error
+
+	// Delay is the wait before the retry.
+	Delay time.Duration
+}
+
+// Reason returns a short human-readable cause of the retry.
+func (ev RetryEvent) Reason() string {
+	switch {
+	case ev.StatusCode != 0:
+		return "status " + strconv.Itoa(ev.StatusCode)
+	case ev.Err != nil:
+		return ev.Err.Error()
+	default:
+		return "stream closed by server"
+	}
+}
+
+// newRetryEvent builds a RetryEvent, extracting the status code from an
+// *APIError cause.
+func newRetryEvent(endpoint string, attempt int, cause error, delay time.Duration) RetryEvent {
+	ev := RetryEvent{Endpoint: endpoint, Attempt: attempt, Err: cause, Delay: delay}
+	var apiErr *APIError
+	if errors.As(cause, &apiErr) {
+		ev.StatusCode = apiErr.StatusCode
+	}
+	return ev
+}
+
+// reportRetry forwards ev to the configured Logger and Metrics, if any.
+func (c *Client) reportRetry(ev RetryEvent) {
+	if c.logger != nil {
+		c.logger.Printf("manaxclient: retry %d for %s in %s: %s", ev.Attempt, ev.Endpoint, ev.Delay, ev.Reason())
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveRetry(ev)
+	}
+}
@@ -0,0 +1,83 @@
+package manaxclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingLogger collects formatted log lines.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+// recordingMetrics collects retry events.
+type recordingMetrics struct {
+	mu      sync.Mutex
+	retries []RetryEvent
+}
+
+func (m *recordingMetrics) ObserveRetry(ev RetryEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, ev)
+}
+
+// TestRetryObservability verifies that every reconnection of a managed
+// stream is reported to Logger and Metrics with endpoint, attempt number,
+// triggering status and delay.
+func TestRetryObservability(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	logger := &recordingLogger{}
+	metrics := &recordingMetrics{}
+	_, server := newTestClient(t, handler)
+	defer server.Close()
+	client, err := NewClientWithOptions(server.URL, WithLogger(logger), WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+
+	store := NewMemoryCursorStore()
+	_ = store.SaveCursor(context.Background(), matchesCursorKey("p_123", MatchingDirectionOffer), Cursor{UpdatedUTC: time.Now(), ID: 1})
+	policy := ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	items, errs := client.ManagedMatchesStream(context.Background(), "p_123", MatchingDirectionOffer, MatchesFilter{}, store, policy)
+	for range items {
+	}
+	if err := <-errs; err == nil {
+		t.Fatalf("expected an error after exhausting reconnect attempts")
+	}
+
+	if len(metrics.retries) != 2 {
+		t.Fatalf("expected 2 retry events, got %#v", metrics.retries)
+	}
+	for i, ev := range metrics.retries {
+		if ev.Endpoint != "/api/matches/items/stream" || ev.Attempt != i+1 || ev.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("unexpected retry event %d: %#v", i, ev)
+		}
+	}
+	if metrics.retries[0].Delay != time.Millisecond || metrics.retries[1].Delay != 2*time.Millisecond {
+		t.Fatalf("unexpected delays: %v, %v", metrics.retries[0].Delay, metrics.retries[1].Delay)
+	}
+
+	if len(logger.lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", logger.lines)
+	}
+	if l := logger.lines[1]; !strings.Contains(l, "retry 2") || !strings.Contains(l, "/api/matches/items/stream") || !strings.Contains(l, "status 503") || !strings.Contains(l, "2ms") {
+		t.Fatalf("unexpected log line: %q", l)
+	}
+}
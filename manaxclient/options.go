@@ -0,0 +1,393 @@
+package manaxclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultConnectivityCheckTimeout is the timeout applied by
+// WithConnectivityCheck when a non-positive duration is passed.
+const DefaultConnectivityCheckTimeout = 5 * time.Second
+
+// DefaultMaxRequestDuration is the default bound on non-streaming
+// requests whose context has no deadline (see WithMaxRequestDuration).
+const DefaultMaxRequestDuration = 10 * time.Minute
+
+// Option configures a Client constructed via NewClientWithOptions.
+// Options are applied in order; an option returning an error aborts
+// construction.
+type Option func(*Client) error
+
+// NewClientWithOptions constructs a Client for baseURL (validated exactly
+// like NewClient) and applies the given options.
+//
+// Without options it is equivalent to NewClient(baseURL, nil) and does not
+// perform any network I/O.
+func NewClientWithOptions(baseURL string, opts ...Option) (*Client, error) {
+	c, err := NewClient(baseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.connectivityTimeout > 0 {
+		if err := c.checkConnectivity(c.connectivityTimeout); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// WithHTTPClient sets the underlying HTTP client (see NewClient).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("WithHTTPClient: httpClient must not be nil")
+		}
+		if c.ownedTransport != nil {
+			return errors.New("WithHTTPClient: conflicts with a transport option applied before it")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithConnectivityCheck makes NewClientWithOptions issue a short HEAD
+// request to the base URL and fail if the server cannot be reached within
+// timeout (DefaultConnectivityCheckTimeout if timeout <= 0).
+//
+// Any HTTP response, including 4xx/5xx, counts as reachable: the check
+// only detects DNS, TCP and TLS failures early instead of on the first call.
+func WithConnectivityCheck(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			timeout = DefaultConnectivityCheckTimeout
+		}
+		c.connectivityTimeout = timeout
+		return nil
+	}
+}
+
+// WithNotFoundAsError makes lookup methods (GetSpeechStatusByID,
+// GetSpeechStatusByKey) report HTTP 404 as (nil, err) with err matching
+// ErrNotFound, so callers can write errors.Is(err, ErrNotFound) instead
+// of inspecting APIError.StatusCode.
+func WithNotFoundAsError() Option {
+	return func(c *Client) error {
+		c.notFoundAsError = true
+		return nil
+	}
+}
+
+// WithDeleteNotFoundOK makes delete methods (DeleteSpeechSession) treat
+// HTTP 404 as "already gone" and return nil, so that cleanup code can be
+// retried safely.
+func WithDeleteNotFoundOK() Option {
+	return func(c *Client) error {
+		c.deleteNotFoundOK = true
+		return nil
+	}
+}
+
+// WithLogger sets a Logger receiving diagnostic messages, for example one
+// line per retry attempt with its endpoint, cause and delay, or one line
+// per server Warning header (see ResponseMeta.Warnings).
+func WithLogger(logger Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithMetrics sets a Metrics sink receiving structured client events.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) error {
+		c.metrics = metrics
+		return nil
+	}
+}
+
+// WithSpeechStatusCache enables an in-memory LRU cache for
+// GetSpeechStatusByID holding up to size entries for ttl each
+// (DefaultSpeechStatusCacheSize / DefaultSpeechStatusCacheTTL when <= 0).
+//
+// Only terminal statuses ("ok", "error") are cached, since they cannot
+// change anymore; pending rows and not-found results are always fetched
+// from the server, so polling loops keep observing progress.
+func WithSpeechStatusCache(size int, ttl time.Duration) Option {
+	return func(c *Client) error {
+		c.statusCache = newStatusCache(size, ttl)
+		return nil
+	}
+}
+
+// WithSupportedSampleRates makes UploadSpeechAudio reject a non-zero
+// SampleRate that is not one of rates before anything is uploaded.
+// A zero SampleRate (server auto-detection) is always accepted.
+//
+// Without this option any positive rate is sent as-is, so that rates
+// added to the server later keep working with older clients.
+func WithSupportedSampleRates(rates ...int) Option {
+	return func(c *Client) error {
+		if len(rates) == 0 {
+			return errors.New("WithSupportedSampleRates: at least one rate is required")
+		}
+		for _, r := range rates {
+			if r <= 0 {
+				return fmt.Errorf("WithSupportedSampleRates: invalid rate %d", r)
+			}
+		}
+		c.supportedSampleRates = slices.Clone(rates)
+		slices.Sort(c.supportedSampleRates)
+		return nil
+	}
+}
+
+// checkSampleRate validates rate against the rates configured with
+// WithSupportedSampleRates.
+func (c *Client) checkSampleRate(rate int) error {
+	if rate == 0 || len(c.supportedSampleRates) == 0 {
+		return nil
+	}
+	if slices.Contains(c.supportedSampleRates, rate) {
+		return nil
+	}
+	supported := make([]string, len(c.supportedSampleRates))
+	for i, r := range c.supportedSampleRates {
+		supported[i] = strconv.Itoa(r)
+	}
+	return fmt.Errorf("unsupported SampleRate %d (supported: %s)", rate, strings.Join(supported, ", "))
+}
+
+// checkConnectivity performs the request configured by WithConnectivityCheck.
+func (c *Client) checkConnectivity(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(withOperation(context.Background(), "ConnectivityCheck"), timeout)
+	defer cancel()
+
+	u := c.BaseURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("connectivity check: create request: %w", err)
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("connectivity check: base URL %s is not reachable: %w", u.String(), err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// WithMaxResponseBytes caps the bytes read from non-streaming response
+// bodies at n. A successful response larger than n fails with an error
+// matching ErrResponseTooLarge; the body of an error response is cut at
+// n bytes and reported with APIError.Truncated.
+//
+// Without this option successful bodies are not limited and error bodies
+// are read up to DefaultMaxErrorBodyBytes. Streams are limited separately
+// by StreamOptions.MaxStreamBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("WithMaxResponseBytes: limit must be positive, got %d", n)
+		}
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
+// WithDefaultProIDFromAuth makes methods taking a profile id (as an
+// argument or a ProID request field) use the profile id configured with
+// SetAuth when they are called with an empty one. An explicit profile id
+// always takes precedence; if neither is set the methods fail as before.
+//
+// It suits single-profile clients. SpeechSession and the streaming loops
+// resolve the profile id once, when they are created or started.
+func WithDefaultProIDFromAuth() Option {
+	return func(c *Client) error {
+		c.defaultProIDFromAuth = true
+		return nil
+	}
+}
+
+// WithMinTLSVersion makes the client refuse TLS connections negotiating a
+// version lower than version (tls.VersionTLS12 or tls.VersionTLS13 in
+// practice).
+//
+// The setting is applied to a transport owned by the client (a clone of
+// http.DefaultTransport), so it cannot be combined with WithHTTPClient or
+// a non-nil httpClient: configure the caller's own transport instead.
+func WithMinTLSVersion(version uint16) Option {
+	return func(c *Client) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return fmt.Errorf("WithMinTLSVersion: unsupported TLS version 0x%04x", version)
+		}
+		t, err := c.transport()
+		if err != nil {
+			return fmt.Errorf("WithMinTLSVersion: %w", err)
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.MinVersion = version
+		return nil
+	}
+}
+
+// WithGzipAudioUploads makes UploadSpeechAudio (and SpeechSession)
+// compress the multipart body of chunks of at least minBytes bytes with
+// gzip and send it with Content-Encoding: gzip. Raw PCM typically shrinks
+// a lot; already compressed formats do not benefit.
+//
+// The compressed body is streamed through a pipe, so memory use stays
+// bounded however large the chunk is. The size of readers that do not
+// report their length (a Len() int method, as on bytes.Reader) is
+// unknown; they are always compressed. Smaller chunks are sent as before.
+//
+// The server must support gzip-encoded request bodies: one that does not
+// typically rejects the upload with 400 or 415.
+func WithGzipAudioUploads(minBytes int64) Option {
+	return func(c *Client) error {
+		if minBytes < 0 {
+			return fmt.Errorf("WithGzipAudioUploads: minBytes must be >= 0, got %d", minBytes)
+		}
+		c.gzipAudio = true
+		c.gzipAudioMinBytes = minBytes
+		return nil
+	}
+}
+
+// WithProfileMismatchCheck makes the client verify that responses echoing
+// a proId (facts and matches snapshots, updates and stream chunks, audio
+// uploads) carry the profile id that was requested. A different value,
+// typically the sign of a proxy or routing bug, fails the call with an
+// error matching ErrProfileMismatch instead of handing another profile's
+// data to the caller; streams stop on the first such chunk. Responses
+// without a proId are accepted.
+//
+// The check is off by default because some deployments legitimately
+// normalize profile ids (case, aliases) in their answers.
+func WithProfileMismatchCheck() Option {
+	return func(c *Client) error {
+		c.checkProfileMismatch = true
+		return nil
+	}
+}
+
+// WithMaxRequestDuration sets the safety net applied to non-streaming
+// requests (including Warmup) made with a context that has no deadline:
+// such a request fails with context.DeadlineExceeded after d instead of
+// hanging forever on a dead connection. It defaults to
+// DefaultMaxRequestDuration, generous enough for large audio uploads;
+// d <= 0 disables it.
+//
+// A deadline set on the caller's context always takes precedence, shorter
+// or longer than d. Streams are never bounded by this setting.
+func WithMaxRequestDuration(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			d = 0
+		}
+		c.maxRequestDuration = d
+		return nil
+	}
+}
+
+// WithMaxConcurrentUploads limits the number of UploadSpeechAudio calls
+// (including those of SpeechSession) in flight on the client to n. Further
+// calls wait for a slot, or fail with ctx.Err() when their context is done
+// first, so that many sessions sharing one Client spread their uploads
+// over time instead of all hitting the server at once. Slots are taken
+// after the request has been validated and held until the response has
+// been read.
+func WithMaxConcurrentUploads(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("WithMaxConcurrentUploads: limit must be positive, got %d", n)
+		}
+		c.uploadSem = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// RequestSigner computes a signature over a request about to be sent and
+// attaches it, typically as a header. A non-nil error aborts the request.
+type RequestSigner func(req *http.Request) error
+
+// WithRequestSigner installs signer, for gateways that authenticate
+// requests with a signature (e.g. an HMAC over method, path and body).
+//
+// signer is called for every request, including streams, warmup and
+// connectivity checks, once all headers are set and right before it is
+// sent: after the WithMiddleware middlewares, so that headers they add
+// are covered. Every attempt is a new request, so stream reconnections and
+// retries are signed again. To sign the body, read it through
+// req.GetBody, which is set whenever the body can be replayed; streamed
+// bodies (gzip audio, large texts) have none. The error of a failing
+// signer is returned wrapped, with ErrRequestSigning.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(c *Client) error {
+		if signer == nil {
+			return errors.New("WithRequestSigner: signer must not be nil")
+		}
+		c.signer = signer
+		return nil
+	}
+}
+
+// sign applies the WithRequestSigner signer, if any, to req.
+func (c *Client) sign(req *http.Request) error {
+	if c.signer == nil {
+		return nil
+	}
+	if err := c.signer(req); err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestSigning, err)
+	}
+	return nil
+}
+
+// WithLenientStreamContentType makes the streaming methods accept 2xx
+// responses whatever their Content-Type, for servers that send SSE
+// without (or with a wrong) text/event-stream type. By default such a
+// response fails with an error matching ErrUnexpectedContentType instead
+// of being parsed as SSE, which would silently yield no events for a JSON
+// or HTML body.
+func WithLenientStreamContentType() Option {
+	return func(c *Client) error {
+		c.lenientStreamContentType = true
+		return nil
+	}
+}
+
+// transport returns the transport owned by the client, creating it (and
+// the *http.Client using it) on first use. It fails when the caller
+// supplied their own HTTP client.
+func (c *Client) transport() (*http.Transport, error) {
+	if c.ownedTransport != nil {
+		return c.ownedTransport, nil
+	}
+	if c.httpClient != nil {
+		return nil, errors.New("cannot change the transport of a caller-supplied http.Client")
+	}
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("http.DefaultTransport is not an *http.Transport")
+	}
+	c.ownedTransport = base.Clone()
+	c.httpClient = &http.Client{Transport: c.ownedTransport}
+	return c.ownedTransport, nil
+}
@@ -0,0 +1,322 @@
+package manaxclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewClientWithOptions_ConnectivityCheckReachable verifies that the
+// connectivity check issues a request and succeeds against a live server,
+// even if the server answers the probe with a non-2xx status.
+func TestNewClientWithOptions_ConnectivityCheckReachable(t *testing.T) {
+	probed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		probed = true
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(srv.URL, WithConnectivityCheck(time.Second))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned error: %v", err)
+	}
+	if c == nil || !probed {
+		t.Fatalf("expected a client and a connectivity probe (probed=%v)", probed)
+	}
+}
+
+// TestNewClientWithOptions_ConnectivityCheckUnreachable verifies that an
+// unreachable base URL is reported at construction time.
+func TestNewClientWithOptions_ConnectivityCheckUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	baseURL := srv.URL
+	srv.Close()
+
+	_, err := NewClientWithOptions(baseURL, WithConnectivityCheck(time.Second))
+	if err == nil {
+		t.Fatalf("expected error for unreachable base URL, got nil")
+	}
+	if !strings.Contains(err.Error(), "not reachable") {
+		t.Fatalf("expected descriptive error, got %v", err)
+	}
+}
+
+// TestNewClientWithOptions_NoCheckByDefault ensures default construction
+// performs no network I/O, even for an unreachable base URL.
+func TestNewClientWithOptions_NoCheckByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	baseURL := srv.URL
+	srv.Close()
+
+	if _, err := NewClientWithOptions(baseURL); err != nil {
+		t.Fatalf("NewClientWithOptions returned error: %v", err)
+	}
+}
+
+// TestWithSupportedSampleRates verifies that a supported rate is uploaded
+// and an unsupported one is rejected client-side with the allowed values.
+func TestWithSupportedSampleRates(t *testing.T) {
+	uploads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(srv.URL, WithSupportedSampleRates(48000, 16000))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned error: %v", err)
+	}
+
+	in := UploadSpeechAudioRequest{
+		ProID:      "p_123",
+		SessionID:  "s_1",
+		Audio:      strings.NewReader("audio"),
+		SampleRate: 16000,
+	}
+	if _, err := c.UploadSpeechAudio(context.Background(), in); err != nil {
+		t.Fatalf("expected supported rate to be accepted, got %v", err)
+	}
+
+	in.Audio = strings.NewReader("audio")
+	in.SampleRate = 22050
+	_, err = c.UploadSpeechAudio(context.Background(), in)
+	if err == nil || !strings.Contains(err.Error(), "supported: 16000, 48000") {
+		t.Fatalf("expected error listing supported rates, got %v", err)
+	}
+	if uploads != 1 {
+		t.Fatalf("expected unsupported rate to be rejected before upload, got %d uploads", uploads)
+	}
+}
+
+// TestWithDefaultProIDFromAuth verifies that an empty proID falls back to
+// the authenticated profile, that an explicit one overrides it, and that
+// calls without either still fail.
+func TestWithDefaultProIDFromAuth(t *testing.T) {
+	var got []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("proId"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}
+	srv := httptest.NewServer(http.HandlerFunc(handler))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(srv.URL, WithDefaultProIDFromAuth())
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := c.GetFactsUpdates(ctx, " ", time.Time{}, 0, 0); err == nil {
+		t.Fatalf("expected error without proID and auth")
+	}
+
+	c.SetAuth("p_auth", "token")
+	if _, err := c.GetFactsUpdates(ctx, "", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("fallback call failed: %v", err)
+	}
+	if _, err := c.GetFactsUpdates(ctx, "p_other", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("override call failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "p_auth" || got[1] != "p_other" {
+		t.Fatalf("expected proId p_auth then p_other, got %q", got)
+	}
+
+	plain, _ := NewClientWithOptions(srv.URL)
+	plain.SetAuth("p_auth", "token")
+	if _, err := plain.GetFactsUpdates(ctx, "", time.Time{}, 0, 0); err == nil {
+		t.Fatalf("expected error without WithDefaultProIDFromAuth")
+	}
+}
+
+// TestWithMinTLSVersion verifies that a server capped below the minimum
+// version is rejected, that a compatible one works, and that the option
+// refuses caller-supplied HTTP clients.
+func TestWithMinTLSVersion(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	newClient := func(version uint16) *Client {
+		t.Helper()
+		c, err := NewClientWithOptions(srv.URL, WithMinTLSVersion(version))
+		if err != nil {
+			t.Fatalf("NewClientWithOptions failed: %v", err)
+		}
+		c.ownedTransport.TLSClientConfig.RootCAs = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+		return c
+	}
+
+	ctx := context.Background()
+	if _, err := newClient(tls.VersionTLS13).GetFactsUpdates(ctx, "p_123", time.Time{}, 0, 0); err == nil {
+		t.Fatalf("expected TLS 1.2 server to be rejected with minimum TLS 1.3")
+	}
+	c := newClient(tls.VersionTLS12)
+	if _, err := c.GetFactsUpdates(ctx, "p_123", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("expected TLS 1.2 to be accepted, got %v", err)
+	}
+	if cfg := c.Config(); cfg.MinTLSVersion != tls.VersionTLS12 || cfg.CustomHTTPClient {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	if _, err := NewClientWithOptions(srv.URL, WithHTTPClient(&http.Client{}), WithMinTLSVersion(tls.VersionTLS12)); err == nil {
+		t.Fatalf("expected error with a caller-supplied HTTP client")
+	}
+	if _, err := NewClientWithOptions(srv.URL, WithMinTLSVersion(tls.VersionTLS12), WithHTTPClient(&http.Client{})); err == nil {
+		t.Fatalf("expected error when WithHTTPClient follows WithMinTLSVersion")
+	}
+	if _, err := NewClientWithOptions(srv.URL, WithMinTLSVersion(0x0200)); err == nil {
+		t.Fatalf("expected error for an invalid version")
+	}
+}
+
+// TestWithMaxRequestDuration verifies that a hung request without a
+// caller deadline fails under the safety net, and that a caller deadline
+// wins over it in both directions.
+func TestWithMaxRequestDuration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(srv.URL, WithMaxRequestDuration(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+	if got := c.Config().MaxRequestDuration; got != 20*time.Millisecond {
+		t.Fatalf("unexpected MaxRequestDuration: %v", got)
+	}
+
+	if _, err := c.GetSpeechStatusByID(context.Background(), 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the safety net to fail the request, got %v", err)
+	}
+	if err := c.Warmup(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the safety net to fail Warmup, got %v", err)
+	}
+
+	// A longer caller deadline is not shortened by the safety net.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := c.GetSpeechStatusByID(ctx, 1); err != nil {
+		t.Fatalf("expected the caller deadline to win, got %v", err)
+	}
+
+	// A shorter caller deadline still applies when the net is generous.
+	c, err = NewClientWithOptions(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+	if got := c.Config().MaxRequestDuration; got != DefaultMaxRequestDuration {
+		t.Fatalf("expected the default safety net, got %v", got)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetSpeechStatusByID(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the caller deadline to fail the request, got %v", err)
+	}
+}
+
+// TestWithRequestSigner verifies that the signer sees the final request,
+// headers and body included, that its header reaches the server, and that
+// every stream (re)connection is signed again.
+func TestWithRequestSigner(t *testing.T) {
+	key := []byte("gateway-secret")
+	sign := func(method, path, proID string, body []byte) string {
+		mac := hmac.New(sha256.New, key)
+		fmt.Fprintf(mac, "%s\n%s\n%s\n", method, path, proID)
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	var streams int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get("X-Signature"), sign(r.Method, r.URL.Path, r.Header.Get("X-Pro-Id"), body); got != want {
+			t.Errorf("%s %s: bad signature %q, want %q", r.Method, r.URL.Path, got, want)
+		}
+		if r.URL.Path == "/api/facts/items/stream" {
+			streams++
+			if streams == 2 {
+				// The second connection ends the test: stop reconnecting.
+				http.Error(w, `{"error":"gone"}`, http.StatusGone)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	signed := 0
+	client := newClientWithOptions(t, server.URL, WithRequestSigner(func(req *http.Request) error {
+		signed++
+		var body []byte
+		if req.GetBody != nil {
+			rc, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+		req.Header.Set("X-Signature", sign(req.Method, req.URL.Path, req.Header.Get("X-Pro-Id"), body))
+		return nil
+	}))
+	client.SetAuth("p_123", "tok")
+	if !client.Config().HasRequestSigner {
+		t.Fatalf("expected HasRequestSigner in the config snapshot")
+	}
+
+	ctx := context.Background()
+	if _, err := client.UploadSpeechText(ctx, UploadSpeechTextRequest{ProID: "p_123", SessionID: "s_1", Text: "hello"}); err != nil {
+		t.Fatalf("UploadSpeechText failed: %v", err)
+	}
+
+	opt := FactsStreamOptions{Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond}}
+	err := client.StreamFactsWithOptions(ctx, "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error { return nil })
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusGone {
+		t.Fatalf("expected the 410 to end the stream, got %v", err)
+	}
+	if signed != 3 || streams != 2 {
+		t.Fatalf("expected 3 signed requests (1 upload, 2 stream connections), got signed=%d streams=%d", signed, streams)
+	}
+
+	// A failing signer aborts the request before it is sent.
+	boom := errors.New("no key")
+	client = newClientWithOptions(t, server.URL, WithRequestSigner(func(*http.Request) error { return boom }))
+	_, err = client.GetSpeechStatusByID(ctx, 1)
+	if !errors.Is(err, ErrRequestSigning) || !errors.Is(err, boom) {
+		t.Fatalf("expected ErrRequestSigning wrapping the signer error, got %v", err)
+	}
+}
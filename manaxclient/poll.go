@@ -0,0 +1,286 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultPollMinInterval is the poll delay used after a non-empty
+	// updates response when PollOptions.MinInterval is not set.
+	DefaultPollMinInterval = time.Second
+
+	// DefaultPollMaxInterval is the upper bound of the adaptive poll
+	// delay when PollOptions.MaxInterval is not set.
+	DefaultPollMaxInterval = 30 * time.Second
+)
+
+// PollOptions configures the adaptive delay of PollFacts and PollMatches.
+//
+// After a response carrying items the next request is issued after
+// MinInterval; every empty response doubles the delay up to MaxInterval.
+// When the server sends an X-Next-Poll-Ms hint, the next request is issued
+// after exactly that delay instead.
+type PollOptions struct {
+	// MinInterval is the shortest delay between two requests.
+	// If <= 0, DefaultPollMinInterval is used.
+	MinInterval time.Duration
+
+	// MaxInterval is the longest delay between two requests.
+	// If <= 0, DefaultPollMaxInterval is used. Values below MinInterval
+	// are raised to MinInterval.
+	MaxInterval time.Duration
+
+	// Jitter is the fraction by which every delay is randomly perturbed,
+	// e.g. 0.2 for ±20%, so that many clients polling on the same
+	// schedule desynchronize. The jittered delay is still clamped to
+	// [MinInterval, MaxInterval]. 0 disables jitter; values are capped at 1.
+	Jitter float64
+
+	// OnPage, if set, is called after every non-empty page has been
+	// passed to the handler, with the number of such pages so far, the
+	// total number of items they carried and the cursor the next request
+	// starts from. It lets callers report progress of a long catch-up;
+	// empty responses are not counted as pages.
+	OnPage func(pagesFetched, itemsSoFar int, lastCursor Cursor)
+
+	// StopAfterEmpty, when positive, ends polling with a nil error after
+	// that many consecutive empty responses: the caller has caught up and
+	// the data is quiet. 0 polls until ctx is done.
+	StopAfterEmpty int
+}
+
+// FactsPollHandler is invoked by PollFacts for every non-empty updates
+// response. Returning a non-nil error stops polling and propagates it.
+type FactsPollHandler func(ctx context.Context, upd *FactsUpdatesResponse) error
+
+// MatchesPollHandler is invoked by PollMatches for every non-empty updates
+// response. Returning a non-nil error stops polling and propagates it.
+type MatchesPollHandler func(ctx context.Context, upd *MatchesUpdatesResponse) error
+
+// PollFacts repeatedly calls GetFactsUpdates starting from cursor and
+// passes every non-empty response to handler, advancing the cursor after
+// the handler returns successfully.
+//
+// It is the polling counterpart of StreamFacts for environments where SSE
+// is not available. The method blocks until ctx is done (returning
+// ctx.Err()), a request fails, the handler returns an error, or
+// opt.StopAfterEmpty consecutive responses were empty (returning nil).
+func (c *Client) PollFacts(
+	ctx context.Context,
+	proID string,
+	cursor Cursor,
+	limit int,
+	opt PollOptions,
+	handler FactsPollHandler,
+) error {
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return errors.New("PollFacts: proID must not be empty")
+	}
+	if handler == nil {
+		return errors.New("PollFacts: handler must not be nil")
+	}
+
+	b := newPollBackoff(opt)
+	progress := pollProgress{onPage: opt.OnPage, stopAfterEmpty: opt.StopAfterEmpty}
+	for {
+		var meta ResponseMeta
+		upd, err := c.GetFactsUpdates(WithResponseMeta(ctx, &meta), proID, cursor.UpdatedUTC, cursor.ID, limit)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("PollFacts: %w", err)
+		}
+
+		if len(upd.Items) > 0 {
+			if err := handler(ctx, upd); err != nil {
+				return err
+			}
+			cursor = Cursor{UpdatedUTC: upd.CursorUpdatedUTC, ID: upd.CursorID}
+			progress.page(len(upd.Items), cursor)
+		}
+		if progress.quiet(len(upd.Items) > 0) {
+			return nil
+		}
+
+		if err := waitOrCancel(ctx, b.delay(len(upd.Items) > 0, meta.NextPollDelay)); err != nil {
+			return err
+		}
+	}
+}
+
+// PollMatches repeatedly calls GetMatchesUpdates starting from cursor and
+// passes every non-empty response to handler, advancing the cursor after
+// the handler returns successfully.
+//
+// direction may be empty to receive both directions. The method blocks
+// until ctx is done (returning ctx.Err()), a request fails, the handler
+// returns an error, or opt.StopAfterEmpty consecutive responses were
+// empty (returning nil).
+func (c *Client) PollMatches(
+	ctx context.Context,
+	proID string,
+	direction MatchingDirection,
+	cursor Cursor,
+	filter MatchesFilter,
+	opt PollOptions,
+	handler MatchesPollHandler,
+) error {
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return errors.New("PollMatches: proID must not be empty")
+	}
+	if handler == nil {
+		return errors.New("PollMatches: handler must not be nil")
+	}
+
+	b := newPollBackoff(opt)
+	progress := pollProgress{onPage: opt.OnPage, stopAfterEmpty: opt.StopAfterEmpty}
+	for {
+		var meta ResponseMeta
+		upd, err := c.GetMatchesUpdates(
+			WithResponseMeta(ctx, &meta),
+			proID,
+			direction,
+			cursor.UpdatedUTC,
+			cursor.ID,
+			filter.MinScore,
+			filter.Limit,
+			filter.MinRationaleLength,
+			filter.MaxRationaleLength,
+		)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("PollMatches: %w", err)
+		}
+
+		if len(upd.Items) > 0 {
+			if err := handler(ctx, upd); err != nil {
+				return err
+			}
+			cursor = Cursor{UpdatedUTC: upd.CursorUpdatedUTC, ID: upd.CursorID}
+			progress.page(len(upd.Items), cursor)
+		}
+		if progress.quiet(len(upd.Items) > 0) {
+			return nil
+		}
+
+		if err := waitOrCancel(ctx, b.delay(len(upd.Items) > 0, meta.NextPollDelay)); err != nil {
+			return err
+		}
+	}
+}
+
+// pollProgress counts the pages of a poll loop for PollOptions.OnPage
+// and its empty responses for PollOptions.StopAfterEmpty.
+type pollProgress struct {
+	onPage         func(pagesFetched, itemsSoFar int, lastCursor Cursor)
+	stopAfterEmpty int
+	pages          int
+	items          int
+	empty          int
+}
+
+// page records a non-empty page of n items ending at cursor.
+func (p *pollProgress) page(n int, cursor Cursor) {
+	p.pages++
+	p.items += n
+	if p.onPage != nil {
+		p.onPage(p.pages, p.items, cursor)
+	}
+}
+
+// quiet records a response and reports whether polling must stop after
+// it because of StopAfterEmpty.
+func (p *pollProgress) quiet(gotItems bool) bool {
+	if gotItems {
+		p.empty = 0
+		return false
+	}
+	p.empty++
+	return p.stopAfterEmpty > 0 && p.empty >= p.stopAfterEmpty
+}
+
+// pollBackoff computes the adaptive, jittered delay between polls.
+type pollBackoff struct {
+	min, max time.Duration
+	cur      time.Duration
+	jitter   float64
+
+	// rnd returns a pseudo-random number in [0, 1); replaceable in tests.
+	rnd func() float64
+}
+
+// newPollBackoff normalizes opt into a pollBackoff.
+func newPollBackoff(opt PollOptions) *pollBackoff {
+	b := &pollBackoff{
+		min:    opt.MinInterval,
+		max:    opt.MaxInterval,
+		jitter: opt.Jitter,
+		rnd:    rand.Float64,
+	}
+	if b.min <= 0 {
+		b.min = DefaultPollMinInterval
+	}
+	if b.max <= 0 {
+		b.max = DefaultPollMaxInterval
+	}
+	if b.max < b.min {
+		b.max = b.min
+	}
+	if b.jitter < 0 {
+		b.jitter = 0
+	}
+	if b.jitter > 1 {
+		b.jitter = 1
+	}
+	return b
+}
+
+// next returns the delay before the following poll. gotItems reports
+// whether the last response carried any items.
+func (b *pollBackoff) next(gotItems bool) time.Duration {
+	switch {
+	case gotItems || b.cur == 0:
+		b.cur = b.min
+	default:
+		b.cur *= 2
+		if b.cur > b.max {
+			b.cur = b.max
+		}
+	}
+	return b.jittered(b.cur)
+}
+
+// delay returns the wait before the next poll: the server hint when
+// positive, otherwise the adaptive delay. The adaptive state advances in
+// both cases so that it stays accurate once hints stop.
+func (b *pollBackoff) delay(gotItems bool, hint time.Duration) time.Duration {
+	d := b.next(gotItems)
+	if hint > 0 {
+		return hint
+	}
+	return d
+}
+
+// jittered perturbs d by up to ±jitter and clamps it to [min, max].
+func (b *pollBackoff) jittered(d time.Duration) time.Duration {
+	if b.jitter > 0 {
+		factor := 1 + b.jitter*(2*b.rnd()-1)
+		d = time.Duration(float64(d) * factor)
+	}
+	if d < b.min {
+		d = b.min
+	}
+	if d > b.max {
+		d = b.max
+	}
+	return d
+}
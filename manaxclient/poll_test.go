@@ -0,0 +1,266 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestPollBackoff_JitterBand verifies that successive jittered delays vary
+// and always stay within ±Jitter of the base delay.
+func TestPollBackoff_JitterBand(t *testing.T) {
+	b := newPollBackoff(PollOptions{
+		MinInterval: 100 * time.Millisecond,
+		MaxInterval: 10 * time.Second,
+		Jitter:      0.2,
+	})
+	b.rnd = rand.New(rand.NewSource(1)).Float64
+
+	base := time.Second
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 50; i++ {
+		d := b.jittered(base)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("delay %v outside ±20%% band of %v", d, base)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 10 {
+		t.Fatalf("expected jittered delays to vary, got %d distinct values", len(seen))
+	}
+}
+
+// TestPollBackoff_JitterRespectsBounds ensures jitter never pushes the
+// delay below MinInterval or above MaxInterval.
+func TestPollBackoff_JitterRespectsBounds(t *testing.T) {
+	b := newPollBackoff(PollOptions{
+		MinInterval: time.Second,
+		MaxInterval: 4 * time.Second,
+		Jitter:      0.5,
+	})
+	b.rnd = rand.New(rand.NewSource(2)).Float64
+
+	for i := 0; i < 50; i++ {
+		d := b.next(i%3 == 0)
+		if d < time.Second || d > 4*time.Second {
+			t.Fatalf("delay %v outside [1s, 4s]", d)
+		}
+	}
+}
+
+// TestPollBackoff_Adaptive verifies the delay doubles on empty responses up
+// to MaxInterval and resets to MinInterval after a non-empty one.
+func TestPollBackoff_Adaptive(t *testing.T) {
+	b := newPollBackoff(PollOptions{MinInterval: time.Second, MaxInterval: 5 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if d := b.next(false); d != w {
+			t.Fatalf("step %d: expected %v, got %v", i, w, d)
+		}
+	}
+	if d := b.next(true); d != time.Second {
+		t.Fatalf("expected reset to 1s after items, got %v", d)
+	}
+}
+
+// TestPollFacts verifies that PollFacts advances the cursor between calls
+// and only passes non-empty responses to the handler.
+func TestPollFacts(t *testing.T) {
+	now := time.Now().UTC()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/facts/items/updates" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		calls++
+		q := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch calls {
+		case 1:
+			if q.Get("sinceId") != "1" || q.Get("limit") != "10" {
+				t.Fatalf("unexpected query on first poll: %v", q)
+			}
+			_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{ProID: "p_123"})
+		case 2:
+			_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{
+				ProID:            "p_123",
+				CursorUpdatedUTC: now,
+				CursorID:         2,
+				Items:            []FactItem{{ID: 2}},
+			})
+		default:
+			if q.Get("sinceId") != "2" {
+				t.Fatalf("expected cursor to advance to 2, got %v", q)
+			}
+			cancel()
+			_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{ProID: "p_123"})
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	handled := 0
+	opt := PollOptions{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Jitter: 0.2}
+	err := client.PollFacts(ctx, "p_123", Cursor{UpdatedUTC: now, ID: 1}, 10, opt, func(ctx context.Context, upd *FactsUpdatesResponse) error {
+		handled++
+		if upd.Items[0].ID != 2 {
+			t.Fatalf("unexpected update: %#v", upd)
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if handled != 1 || calls != 3 {
+		t.Fatalf("unexpected counts: handled=%d calls=%d", handled, calls)
+	}
+}
+
+// TestPollMatches_NextPollHint verifies that the X-Next-Poll-Ms header
+// overrides the adaptive delay: with a one-hour MinInterval the loop
+// would otherwise never reach its third request.
+func TestPollMatches_NextPollHint(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		w.Header().Set("X-Next-Poll-Ms", "5")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123"})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	opt := PollOptions{MinInterval: time.Hour, MaxInterval: time.Hour}
+	err := client.PollMatches(ctx, "p_123", MatchingDirectionOffer, Cursor{UpdatedUTC: time.Now(), ID: 1}, MatchesFilter{}, opt,
+		func(ctx context.Context, upd *MatchesUpdatesResponse) error { return nil })
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled after 3 polls, got %v (calls=%d)", err, calls)
+	}
+}
+
+// TestWithResponseMeta verifies that doJSON records status, headers and
+// the poll hint in the ResponseMeta attached to the context.
+func TestWithResponseMeta(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Next-Poll-Ms", "1500")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{ProID: "p_123"})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var meta ResponseMeta
+	if _, err := client.GetFactsUpdates(WithResponseMeta(context.Background(), &meta), "p_123", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("GetFactsUpdates returned error: %v", err)
+	}
+	if meta.StatusCode != http.StatusOK || meta.NextPollDelay != 1500*time.Millisecond || meta.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected meta: %#v", meta)
+	}
+}
+
+// TestPollOptions_OnPage verifies that OnPage fires once per non-empty page
+// with increasing counts and the cursor of that page.
+func TestPollOptions_OnPage(t *testing.T) {
+	now := time.Now().UTC()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		switch calls {
+		case 1:
+			_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123", CursorUpdatedUTC: now, CursorID: 2,
+				Items: []MatchItem{{ID: 1}, {ID: 2}}})
+		case 2:
+			_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123"})
+		case 3:
+			_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123", CursorUpdatedUTC: now, CursorID: 5,
+				Items: []MatchItem{{ID: 5}}})
+		default:
+			cancel()
+			_ = json.NewEncoder(w).Encode(MatchesUpdatesResponse{ProID: "p_123"})
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	type progress struct {
+		pages, items int
+		cursor       Cursor
+	}
+	var got []progress
+	opt := PollOptions{
+		MinInterval: time.Millisecond,
+		MaxInterval: time.Millisecond,
+		OnPage: func(pagesFetched, itemsSoFar int, lastCursor Cursor) {
+			got = append(got, progress{pagesFetched, itemsSoFar, lastCursor})
+		},
+	}
+	err := client.PollMatches(ctx, "p_123", MatchingDirectionOffer, Cursor{}, MatchesFilter{}, opt,
+		func(ctx context.Context, upd *MatchesUpdatesResponse) error { return nil })
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(got) != 2 ||
+		got[0].pages != 1 || got[0].items != 2 || got[0].cursor.ID != 2 ||
+		got[1].pages != 2 || got[1].items != 3 || got[1].cursor.ID != 5 {
+		t.Fatalf("unexpected progress: %+v", got)
+	}
+}
+
+// TestPollOptions_StopAfterEmpty verifies that polling ends with nil after
+// the configured number of consecutive empty responses, the count being
+// reset by a non-empty one.
+func TestPollOptions_StopAfterEmpty(t *testing.T) {
+	now := time.Now().UTC()
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		upd := FactsUpdatesResponse{ProID: "p_123"}
+		if calls == 1 || calls == 3 {
+			upd.CursorUpdatedUTC, upd.CursorID = now, int64(calls)
+			upd.Items = []FactItem{{ID: int64(calls)}}
+		}
+		_ = json.NewEncoder(w).Encode(upd)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	handled := 0
+	opt := PollOptions{MinInterval: time.Millisecond, MaxInterval: time.Millisecond, StopAfterEmpty: 2}
+	err := client.PollFacts(ctx, "p_123", Cursor{}, 0, opt, func(ctx context.Context, upd *FactsUpdatesResponse) error {
+		handled++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected a clean stop, got %v", err)
+	}
+	// items, empty, items, empty, empty.
+	if calls != 5 || handled != 2 {
+		t.Fatalf("unexpected counts: calls=%d handled=%d", calls, handled)
+	}
+}
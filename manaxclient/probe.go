@@ -0,0 +1,120 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultProbeStreamingWindow is how long ProbeStreaming waits for the
+// first event or keepalive when ctx has no earlier deadline.
+const DefaultProbeStreamingWindow = 3 * time.Second
+
+// ProbeStreaming briefly opens the facts stream for proID and reports
+// whether the server's events reach the client unbuffered, so that an
+// application can fall back to PollFacts behind a buffering proxy.
+//
+// It returns true as soon as any SSE event, including a keepalive
+// comment, is received within DefaultProbeStreamingWindow (or before the
+// ctx deadline, if sooner), then closes the connection. It returns false
+// with a nil error when nothing arrives in time, when the response is
+// fully buffered (see StreamOptions.RejectNonStreaming) or when the
+// server closes the stream without sending anything.
+//
+// Errors are returned for non-2xx responses, transport failures and
+// cancellation of ctx.
+func (c *Client) ProbeStreaming(ctx context.Context, proID string) (bool, error) {
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return false, errors.New("ProbeStreaming: proID must not be empty")
+	}
+
+	probeCtx, cancel := context.WithTimeout(withOperation(ctx, "ProbeStreaming"), DefaultProbeStreamingWindow)
+	defer cancel()
+
+	q := url.Values{}
+	q.Set("proId", proID)
+
+	req, err := c.newRequest(probeCtx, http.MethodGet, "/api/facts/items/stream", q, nil)
+	if err != nil {
+		return false, fmt.Errorf("ProbeStreaming: create request: %w", err)
+	}
+
+	h := http.Header{}
+	h.Set("Accept", "text/event-stream")
+	c.applyHeaders(req, h)
+
+	start := time.Now()
+	resp, err := c.send(req)
+	if err != nil {
+		return probeTimedOut(ctx, probeCtx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, c.readAPIError(resp, start)
+	}
+	if isNonStreamingResponse(resp) {
+		return false, nil
+	}
+
+	if _, err := newSSEReader(resp.Body).ReadEvent(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return probeTimedOut(ctx, probeCtx, err)
+	}
+	return true, nil
+}
+
+// probeTimedOut classifies a ProbeStreaming failure: expiry of the probe
+// window (or of the ctx deadline) means "not streaming", cancellation and
+// other errors are reported.
+func probeTimedOut(ctx, probeCtx context.Context, err error) (bool, error) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return false, ctx.Err()
+	}
+	if probeCtx.Err() != nil {
+		return false, nil
+	}
+	return false, fmt.Errorf("ProbeStreaming: %w", err)
+}
+
+// DrainFacts opens the facts stream for proID, discards every facts event
+// received during the window d and returns how many arrived. It is meant
+// for smoke tests: unlike ProbeStreaming, which stops at the first event,
+// it measures the event flow over a fixed duration.
+//
+// Expiry of the window ends an established stream cleanly with a nil
+// error, as does the server closing the stream earlier. Keepalive
+// comments are not counted. Errors are returned, with the count so far,
+// for non-2xx responses, transport failures (including a server that
+// does not answer within the window) and cancellation or expiry of ctx.
+func (c *Client) DrainFacts(ctx context.Context, proID string, d time.Duration) (int, error) {
+	if d <= 0 {
+		return 0, fmt.Errorf("DrainFacts: duration must be positive, got %v", d)
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	n := 0
+	connected := false
+	var opt FactsStreamOptions
+	opt.onConnected = func() { connected = true }
+	err := c.streamFacts(drainCtx, "DrainFacts", proID, opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		n++
+		return nil
+	})
+	if err != nil && ctx.Err() == nil && drainCtx.Err() != nil {
+		if !connected {
+			return n, fmt.Errorf("DrainFacts: stream not established within %v: %w", d, err)
+		}
+		return n, nil
+	}
+	return n, err
+}
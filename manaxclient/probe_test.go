@@ -0,0 +1,114 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestProbeStreaming_Streaming verifies that a keepalive flushed right
+// away is detected as working streaming.
+func TestProbeStreaming_Streaming(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/facts/items/stream" || r.URL.Query().Get("proId") != "p_123" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": ping\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ok, err := client.ProbeStreaming(context.Background(), "p_123")
+	if err != nil || !ok {
+		t.Fatalf("expected streaming to be detected, got ok=%v err=%v", ok, err)
+	}
+}
+
+// TestProbeStreaming_Buffered verifies that a fully buffered response and
+// a connection on which nothing arrives are both reported as false.
+func TestProbeStreaming_Buffered(t *testing.T) {
+	body := ": ping\n\nevent: facts\ndata: {}\n\n"
+	buffered := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write([]byte(body))
+	}
+	silent := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	for name, h := range map[string]http.HandlerFunc{"buffered": buffered, "silent": silent} {
+		client, server := newTestClient(t, h)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		ok, err := client.ProbeStreaming(ctx, "p_123")
+		cancel()
+		server.Close()
+
+		if err != nil || ok {
+			t.Fatalf("%s: expected ok=false without error, got ok=%v err=%v", name, ok, err)
+		}
+	}
+}
+
+// TestDrainFacts verifies that DrainFacts counts the events received
+// within the window, ignores keepalives and ends cleanly at its deadline.
+func TestDrainFacts(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", CursorID: int64(i + 1)})
+			_, _ = w.Write([]byte(": ping\n\n"))
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	start := time.Now()
+	n, err := client.DrainFacts(context.Background(), "p_123", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected a clean end at the deadline, got %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 events, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("expected DrainFacts to last about the window, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.DrainFacts(ctx, "p_123", time.Second); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestDrainFacts_NoResponse verifies that a server accepting the
+// connection but never answering is reported as an error, not as a quiet
+// stream.
+func TestDrainFacts_NoResponse(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	n, err := client.DrainFacts(context.Background(), "p_123", 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || n != 0 {
+		t.Fatalf("expected a deadline error with no events, got %d, %v", n, err)
+	}
+}
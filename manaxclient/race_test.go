@@ -0,0 +1,7 @@
+//go:build race
+
+package manaxclient
+
+// raceEnabled is true when tests run under the race detector, which
+// randomly drops sync.Pool entries and so inflates allocation counts.
+const raceEnabled = true
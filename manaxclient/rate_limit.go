@@ -0,0 +1,156 @@
+package manaxclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitStatus is the request quota reported by the server in
+// rate-limit headers, either the common X-RateLimit-Limit /
+// X-RateLimit-Remaining / X-RateLimit-Reset set or the RateLimit-Limit /
+// RateLimit-Remaining / RateLimit-Reset headers of the IETF draft.
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed in the current window, or
+	// 0 when the server did not report it.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is when the window ends and the quota is replenished; zero
+	// when the server did not report it.
+	Reset time.Time
+
+	// ObservedAt is when the response carrying the headers was received.
+	ObservedAt time.Time
+}
+
+// Exhausted reports whether no request is left in the current window. It
+// stays true after Reset has passed; compare Reset with the current time
+// to decide when to resume.
+func (s RateLimitStatus) Exhausted() bool {
+	return s.Remaining <= 0
+}
+
+// resetEpochThreshold separates the two meanings of a reset value: larger
+// values are Unix times in seconds (the usual X-RateLimit-Reset form),
+// smaller ones a number of seconds from now (the RateLimit-Reset form).
+const resetEpochThreshold = 1_000_000_000
+
+// parseRateLimit extracts the rate-limit state from h, preferring the
+// X-RateLimit-* headers over the RateLimit-* ones. It returns nil when no
+// valid remaining count is present. now is the time the response was
+// received, used for relative reset values.
+func parseRateLimit(h http.Header, now time.Time) *RateLimitStatus {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, ok := rateLimitInt(h.Get(prefix + "Remaining"))
+		if !ok {
+			continue
+		}
+		st := &RateLimitStatus{Remaining: remaining, ObservedAt: now}
+		st.Limit, _ = rateLimitInt(h.Get(prefix + "Limit"))
+		if reset, ok := rateLimitInt(h.Get(prefix + "Reset")); ok {
+			if reset >= resetEpochThreshold {
+				st.Reset = time.Unix(int64(reset), 0)
+			} else {
+				st.Reset = now.Add(time.Duration(reset) * time.Second)
+			}
+		}
+		return st
+	}
+	return nil
+}
+
+// rateLimitInt parses a non-negative rate-limit header value. Values
+// listing several policies ("100, 1000;w=3600") yield the first one.
+func rateLimitInt(v string) (int, bool) {
+	v, _, _ = strings.Cut(v, ",")
+	v, _, _ = strings.Cut(v, ";")
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// recordRateLimit remembers the rate-limit state of resp for
+// LastRateLimit, if it carries one.
+func (c *Client) recordRateLimit(resp *http.Response) {
+	st := parseRateLimit(resp.Header, time.Now())
+	if st == nil {
+		return
+	}
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.lastRateLimit = st
+}
+
+// LastRateLimit returns the rate-limit state reported by the most recent
+// response carrying rate-limit headers, whatever the call, status or
+// profile; ok is false until such a response has been received.
+//
+// Callers can throttle preemptively, e.g. by waiting until Reset when
+// Exhausted reports true, instead of running into 429 responses. The
+// state of a single call is available through ResponseMeta.RateLimit.
+func (c *Client) LastRateLimit() (status RateLimitStatus, ok bool) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	if c.lastRateLimit == nil {
+		return RateLimitStatus{}, false
+	}
+	return *c.lastRateLimit, true
+}
+
+// RateLimitHandler wraps a stream handler (FactsStreamHandler,
+// MatchesStreamHandler or any func(ctx, *T) error) so that it runs at
+// most rps times per second, evenly spaced. It suits handlers issuing
+// follow-up requests, such as PatchFactReviewStatus for every streamed
+// fact, which could otherwise overwhelm the server during a burst.
+//
+// A call arriving too early waits for its slot, or returns ctx.Err() if
+// ctx is done first. Since handlers run synchronously, the wait also
+// delays reading the stream: keep StreamOptions.IdleTimeout well above
+// the spacing. The returned handler is safe for concurrent use and shares
+// one budget across its callers. rps <= 0 returns h unchanged.
+//
+// Server-reported quotas are not taken into account; combine with
+// Client.LastRateLimit to back off further when they run low.
+func RateLimitHandler[H ~func(context.Context, *T) error, T any](h H, rps float64) H {
+	if rps <= 0 {
+		return h
+	}
+	l := &intervalLimiter{interval: time.Duration(float64(time.Second) / rps)}
+	return func(ctx context.Context, v *T) error {
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
+		return h(ctx, v)
+	}
+}
+
+// intervalLimiter spaces events at least interval apart.
+type intervalLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next free slot, which it reserves, or until ctx
+// is done.
+func (l *intervalLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return waitOrCancel(ctx, at.Sub(now))
+}
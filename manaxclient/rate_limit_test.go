@@ -0,0 +1,137 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestParseRateLimit verifies both header families, absolute and relative
+// reset values, and that responses without a remaining count yield nil.
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "100")
+	h.Set("X-RateLimit-Remaining", "42")
+	h.Set("X-RateLimit-Reset", "1800000060")
+	st := parseRateLimit(h, now)
+	if st == nil || st.Limit != 100 || st.Remaining != 42 || !st.Reset.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected X-RateLimit status: %+v", st)
+	}
+
+	h = http.Header{}
+	h.Set("RateLimit-Limit", "10, 10;w=1, 1000;w=3600")
+	h.Set("RateLimit-Remaining", "0")
+	h.Set("RateLimit-Reset", "30")
+	st = parseRateLimit(h, now)
+	if st == nil || st.Limit != 10 || !st.Exhausted() || !st.Reset.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("unexpected RateLimit status: %+v", st)
+	}
+
+	h = http.Header{}
+	h.Set("X-RateLimit-Limit", "100")
+	h.Set("X-RateLimit-Remaining", "many")
+	if st := parseRateLimit(h, now); st != nil {
+		t.Fatalf("expected nil without a valid remaining count, got %+v", st)
+	}
+}
+
+// TestLastRateLimit verifies that the rate-limit headers of each response
+// are exposed in ResponseMeta and remembered by the client, including on
+// error responses, and that responses without them keep the last state.
+func TestLastRateLimit(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("X-RateLimit-Limit", "5")
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(2-calls))
+			w.Header().Set("X-RateLimit-Reset", "10")
+		}
+		if calls == 2 {
+			http.Error(w, `{"error":"slow down"}`, http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{ProID: "p_123"})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	if _, ok := client.LastRateLimit(); ok {
+		t.Fatalf("expected no rate-limit state before any response")
+	}
+
+	var meta ResponseMeta
+	ctx := WithResponseMeta(context.Background(), &meta)
+	if _, err := client.GetFactsUpdates(ctx, "p_123", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("GetFactsUpdates failed: %v", err)
+	}
+	if meta.RateLimit == nil || meta.RateLimit.Limit != 5 || meta.RateLimit.Remaining != 1 {
+		t.Fatalf("unexpected meta rate limit: %+v", meta.RateLimit)
+	}
+
+	var apiErr *APIError
+	if _, err := client.GetFactsUpdates(ctx, "p_123", time.Time{}, 0, 0); !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	st, ok := client.LastRateLimit()
+	if !ok || !st.Exhausted() || time.Until(st.Reset) <= 0 {
+		t.Fatalf("unexpected state after 429: %+v (ok=%v)", st, ok)
+	}
+
+	if _, err := client.GetFactsUpdates(ctx, "p_123", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("GetFactsUpdates failed: %v", err)
+	}
+	if meta.RateLimit != nil {
+		t.Fatalf("expected no meta rate limit without headers, got %+v", meta.RateLimit)
+	}
+	if st, ok := client.LastRateLimit(); !ok || st.Remaining != 0 {
+		t.Fatalf("expected the last reported state to be kept, got %+v", st)
+	}
+}
+
+// TestRateLimitHandler verifies that the wrapped handler runs no more
+// often than the configured rate and that a waiting call honors ctx.
+func TestRateLimitHandler(t *testing.T) {
+	var calls []time.Time
+	var h FactsStreamHandler = func(ctx context.Context, chunk *FactsStreamChunk) error {
+		calls = append(calls, time.Now())
+		return nil
+	}
+	limited := RateLimitHandler(h, 50) // one call every 20ms
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limited(context.Background(), &FactsStreamChunk{}); err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("expected 5 calls to take at least 80ms, took %v", elapsed)
+	}
+	for i := 1; i < len(calls); i++ {
+		if gap := calls[i].Sub(calls[i-1]); gap < 15*time.Millisecond {
+			t.Fatalf("calls %d and %d only %v apart", i-1, i, gap)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limited(ctx, &FactsStreamChunk{}); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(calls) != 5 {
+		t.Fatalf("expected the cancelled call not to run the handler, got %d calls", len(calls))
+	}
+
+	if got := RateLimitHandler(h, 0); got == nil {
+		t.Fatalf("expected rps <= 0 to return the handler")
+	}
+}
@@ -0,0 +1,98 @@
+package manaxclient
+
+import (
+	"context"
+	"strings"
+)
+
+// preserveTrailingSlashKey is the context key set by PreserveTrailingSlash.
+type preserveTrailingSlashKey struct{}
+
+// PreserveTrailingSlash returns a copy of ctx that makes requests issued
+// with it keep a trailing slash present in the endpoint path, for servers
+// that distinguish "/items" from "/items/".
+//
+// By default the client normalizes endpoint paths: duplicate slashes are
+// collapsed and trailing slashes removed. With this setting only the
+// trailing slash is kept; duplicate slashes are still collapsed.
+func PreserveTrailingSlash(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preserveTrailingSlashKey{}, true)
+}
+
+// preservesTrailingSlash reports whether ctx was derived from
+// PreserveTrailingSlash.
+func preservesTrailingSlash(ctx context.Context) bool {
+	v, _ := ctx.Value(preserveTrailingSlashKey{}).(bool)
+	return v
+}
+
+// profileAuthKey is the context key set by WithProfileAuth.
+type profileAuthKey struct{}
+
+// profileAuth is the per-call identity stored by WithProfileAuth.
+type profileAuth struct {
+	proID string
+	token string
+}
+
+// WithProfileAuth returns a copy of ctx that makes requests issued with it
+// send proID and token as X-Pro-Id / X-Pro-Token instead of the identity
+// configured with SetAuth. Both headers are replaced: an empty value
+// omits the header rather than falling back to the client's.
+//
+// It lets one shared Client act for many profiles concurrently without
+// calling SetAuth. With WithDefaultProIDFromAuth, methods called with an
+// empty profile id use proID.
+func WithProfileAuth(ctx context.Context, proID, token string) context.Context {
+	return context.WithValue(ctx, profileAuthKey{}, profileAuth{
+		proID: strings.TrimSpace(proID),
+		token: strings.TrimSpace(token),
+	})
+}
+
+// profileAuthFromContext returns the identity set by WithProfileAuth.
+func profileAuthFromContext(ctx context.Context) (profileAuth, bool) {
+	if ctx == nil {
+		return profileAuth{}, false
+	}
+	auth, ok := ctx.Value(profileAuthKey{}).(profileAuth)
+	return auth, ok
+}
+
+// operationKey is the context key under which the client records the
+// name of the public method issuing a request.
+type operationKey struct{}
+
+// withOperation returns ctx tagged with op, the public method name. A nil
+// ctx is returned unchanged so that callers can keep reporting it.
+func withOperation(ctx context.Context, op string) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// withDefaultOperation tags ctx with op unless it already carries an
+// operation, so that generic helpers keep the name of the method built
+// on top of them.
+func withDefaultOperation(ctx context.Context, op string) context.Context {
+	if ctx == nil || OperationFromContext(ctx) != "" {
+		return ctx
+	}
+	return withOperation(ctx, op)
+}
+
+// OperationFromContext returns the name of the client method (for example
+// "GetFactsSnapshot") that issued the request carrying ctx, or "" if ctx
+// does not come from a client call.
+//
+// Every request sent by the client carries the tag in its context, so a
+// custom http.RoundTripper, Logger or Metrics sink can label requests by
+// logical operation via OperationFromContext(req.Context()).
+func OperationFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
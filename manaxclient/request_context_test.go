@@ -0,0 +1,164 @@
+package manaxclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// opRecorder is an http.RoundTripper middleware recording the operation
+// tag of every request before forwarding it.
+type opRecorder struct {
+	mu   sync.Mutex
+	ops  []string
+	next http.RoundTripper
+}
+
+func (o *opRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.mu.Lock()
+	o.ops = append(o.ops, OperationFromContext(req.Context()))
+	o.mu.Unlock()
+	return o.next.RoundTrip(req)
+}
+
+// last returns the most recently recorded operation.
+func (o *opRecorder) last() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.ops) == 0 {
+		return ""
+	}
+	return o.ops[len(o.ops)-1]
+}
+
+// TestOperationFromContext verifies that every client method tags its
+// requests with its own name, visible to transport middleware.
+func TestOperationFromContext(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stream") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	_, server := newTestClient(t, handler)
+	defer server.Close()
+
+	rec := &opRecorder{next: http.DefaultTransport}
+	client, err := NewClient(server.URL, &http.Client{Transport: rec})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	audio := UploadSpeechAudioRequest{ProID: "p", SessionID: "s", Audio: strings.NewReader("a")}
+	calls := []struct {
+		op   string
+		call func() error
+	}{
+		{"CreateProWallet", func() error { _, err := client.CreateProWallet(ctx, ""); return err }},
+		{"VerifyProWallet", func() error { _, err := client.VerifyProWallet(ctx, "p", "t"); return err }},
+		{"UploadSpeechAudio", func() error { _, err := client.UploadSpeechAudio(ctx, audio); return err }},
+		{"UploadSpeechText", func() error {
+			_, err := client.UploadSpeechText(ctx, UploadSpeechTextRequest{ProID: "p", SessionID: "s", Text: "x"})
+			return err
+		}},
+		{"GetSpeechStatusByID", func() error { _, err := client.GetSpeechStatusByID(ctx, 1); return err }},
+		{"GetSpeechStatusByKey", func() error { _, err := client.GetSpeechStatusByKey(ctx, "p", "s", 0); return err }},
+		{"DeleteSpeechSession", func() error { return client.DeleteSpeechSession(ctx, "p", "s") }},
+		{"GetFactsSnapshot", func() error { _, err := client.GetFactsSnapshot(ctx, "p", 0); return err }},
+		{"GetFactsSnapshotWithRequest", func() error {
+			_, err := client.GetFactsSnapshotWithRequest(ctx, FactsSnapshotRequest{ProID: "p"})
+			return err
+		}},
+		{"GetFactsUpdates", func() error { _, err := client.GetFactsUpdates(ctx, "p", now, 0, 0); return err }},
+		{"PatchFactReviewStatus", func() error { _, err := client.PatchFactReviewStatus(ctx, "p", 1, "ok"); return err }},
+		{"GetMatchesSnapshot", func() error {
+			_, err := client.GetMatchesSnapshot(ctx, "p", MatchingDirectionOffer, 0, 0, 0, 0)
+			return err
+		}},
+		{"GetMatchesUpdates", func() error {
+			_, err := client.GetMatchesUpdates(ctx, "p", "", now, 0, 0, 0, 0, 0)
+			return err
+		}},
+		{"StreamFacts", func() error {
+			return client.StreamFacts(ctx, "p", func(context.Context, *FactsStreamChunk) error { return nil })
+		}},
+		{"StreamMatches", func() error {
+			return client.StreamMatches(ctx, "p", Cursor{UpdatedUTC: now}, MatchesStreamOptions{Direction: MatchingDirectionOffer},
+				func(context.Context, *MatchesStreamChunk) error { return nil })
+		}},
+		{"GetJSON", func() error { _, err := GetJSON[struct{}](ctx, client, "/api/x", nil); return err }},
+	}
+
+	for _, tc := range calls {
+		if err := tc.call(); err != nil {
+			t.Fatalf("%s returned error: %v", tc.op, err)
+		}
+		if got := rec.last(); got != tc.op {
+			t.Fatalf("expected operation %q in middleware, got %q", tc.op, got)
+		}
+	}
+
+	if op := OperationFromContext(ctx); op != "" {
+		t.Fatalf("caller context must not be modified, got %q", op)
+	}
+}
+
+// TestWithProfileAuth verifies that concurrent calls on one client send
+// their own per-call credentials (also used as the default proId), and
+// that calls without them keep the SetAuth identity.
+func TestWithProfileAuth(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]string{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		id, token := r.Header.Get("X-Pro-Id"), r.Header.Get("X-Pro-Token")
+		if q := r.URL.Query().Get("proId"); q != id {
+			t.Errorf("proId %q does not match X-Pro-Id %q", q, id)
+		}
+		mu.Lock()
+		seen[id] = token
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	client := newClientWithOptions(t, server.URL, WithDefaultProIDFromAuth())
+	client.SetAuth("p_shared", "t_shared")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := WithProfileAuth(context.Background(), fmt.Sprintf("p_%d", i), fmt.Sprintf("t_%d", i))
+			if _, err := client.GetFactsUpdates(ctx, "", time.Time{}, 0, 0); err != nil {
+				t.Errorf("GetFactsUpdates failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, err := client.GetFactsUpdates(context.Background(), "", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("GetFactsUpdates failed: %v", err)
+	}
+
+	if len(seen) != 21 || seen["p_shared"] != "t_shared" {
+		t.Fatalf("unexpected identities: %v", seen)
+	}
+	for i := 0; i < 20; i++ {
+		if tok := seen[fmt.Sprintf("p_%d", i)]; tok != fmt.Sprintf("t_%d", i) {
+			t.Fatalf("profile p_%d sent token %q", i, tok)
+		}
+	}
+}
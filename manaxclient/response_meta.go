@@ -0,0 +1,187 @@
+package manaxclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseMeta exposes transport-level details of the last response
+// received by a non-streaming call made with a context returned by
+// WithResponseMeta.
+type ResponseMeta struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// Header is the full response header set.
+	Header http.Header
+
+	// NextPollDelay is the delay recommended by the server before the
+	// next poll, from the X-Next-Poll-Ms header; 0 when absent or invalid.
+	NextPollDelay time.Duration
+
+	// Warnings holds the non-fatal notices (deprecations, clamped
+	// parameters, ...) sent in Warning headers, in order; nil when there
+	// are none. See parseWarnings for the format.
+	Warnings []string
+
+	// RateLimit is the rate-limit state carried by the response headers,
+	// or nil when there is none (see RateLimitStatus).
+	RateLimit *RateLimitStatus
+}
+
+// responseMetaKey is the context key set by WithResponseMeta.
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a copy of ctx that makes the client fill *meta
+// with the details of every response received for requests issued with
+// it. When several requests share the context, *meta reflects the last
+// one. meta must not be read concurrently with such requests.
+func WithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+// captureResponseMeta fills the ResponseMeta attached to ctx, if any.
+func captureResponseMeta(ctx context.Context, resp *http.Response) {
+	meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if meta == nil {
+		return
+	}
+	*meta = ResponseMeta{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header.Clone(),
+		NextPollDelay: parseNextPollDelay(resp.Header),
+		Warnings:      parseWarnings(resp.Header),
+		RateLimit:     parseRateLimit(resp.Header, time.Now()),
+	}
+}
+
+// logWarnings forwards the Warning headers of resp to the configured
+// Logger, if any.
+func (c *Client) logWarnings(ctx context.Context, resp *http.Response) {
+	if c.logger == nil {
+		return
+	}
+	for _, w := range parseWarnings(resp.Header) {
+		c.logger.Printf("manaxclient: %s: server warning: %s", OperationFromContext(ctx), w)
+	}
+}
+
+// parseWarnings returns the warnings carried by the Warning headers of h.
+// Values in the RFC 7234 form `299 - "text" "date"` yield their quoted
+// text; any other value is kept as-is. A header may list several
+// comma-separated warnings.
+func parseWarnings(h http.Header) []string {
+	var out []string
+	for _, v := range h.Values("Warning") {
+		for _, w := range splitWarningList(v) {
+			if w = strings.TrimSpace(w); w == "" {
+				continue
+			}
+			out = append(out, warningText(w))
+		}
+	}
+	return out
+}
+
+// splitWarningList splits v at commas outside quoted strings.
+func splitWarningList(v string) []string {
+	var parts []string
+	quoted, escaped, start := false, false, 0
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			parts = append(parts, v[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, v[start:])
+}
+
+// warningText extracts the quoted warn-text of a single RFC 7234 warning
+// (3-digit code, agent, quoted text, optional date), or returns w
+// unchanged when it does not have that form.
+func warningText(w string) string {
+	code, rest, ok := strings.Cut(w, " ")
+	if !ok || len(code) != 3 {
+		return w
+	}
+	if _, err := strconv.Atoi(code); err != nil {
+		return w
+	}
+	_, rest, ok = strings.Cut(strings.TrimSpace(rest), " ")
+	rest = strings.TrimSpace(rest)
+	if !ok || !strings.HasPrefix(rest, `"`) {
+		return w
+	}
+	if text, err := strconv.Unquote(rest[:quotedEnd(rest)]); err == nil {
+		return text
+	}
+	return w
+}
+
+// quotedEnd returns the index just past the quoted string s starts with,
+// or len(s) if it is unterminated.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// parseNextPollDelay reads the X-Next-Poll-Ms hint from h.
+func parseNextPollDelay(h http.Header) time.Duration {
+	v := strings.TrimSpace(h.Get("X-Next-Poll-Ms"))
+	if v == "" {
+		return 0
+	}
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// parseRetryAfter reads the Retry-After header from h, given either as
+// delay-seconds or as an HTTP date relative to now. Missing, invalid and
+// past values yield 0.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	t, err := http.ParseTime(v)
+	if err != nil || !t.After(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
+
+// isTruthyHeader reports whether a flag header value is set: any
+// non-empty value other than "0", "false" or "no".
+func isTruthyHeader(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "false", "no":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestResponseMeta_Warnings verifies that Warning headers are exposed in
+// ResponseMeta.Warnings and forwarded to the Logger.
+func TestResponseMeta_Warnings(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Warning", `299 - "limit clamped to 500"`)
+		w.Header().Add("Warning", `299 api.manax.pro "since is deprecated, use cursor" "Sat, 01 Aug 2026 00:00:00 GMT"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(FactsUpdatesResponse{ProID: "p_123"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	logger := &recordingLogger{}
+	client := newClientWithOptions(t, server.URL, WithLogger(logger))
+
+	var meta ResponseMeta
+	if _, err := client.GetFactsUpdates(WithResponseMeta(context.Background(), &meta), "p_123", time.Time{}, 0, 0); err != nil {
+		t.Fatalf("GetFactsUpdates failed: %v", err)
+	}
+	want := []string{"limit clamped to 500", "since is deprecated, use cursor"}
+	if !reflect.DeepEqual(meta.Warnings, want) {
+		t.Fatalf("expected warnings %q, got %q", want, meta.Warnings)
+	}
+	if len(logger.lines) != 2 || !strings.Contains(logger.lines[0], "GetFactsUpdates: server warning: limit clamped to 500") {
+		t.Fatalf("unexpected log lines: %q", logger.lines)
+	}
+}
+
+// TestParseWarnings covers the RFC 7234 form, comma-separated lists and
+// free-form values.
+func TestParseWarnings(t *testing.T) {
+	cases := []struct {
+		values []string
+		want   []string
+	}{
+		{nil, nil},
+		{[]string{`199 - "a, b" , 299 - "c \"q\""`}, []string{"a, b", `c "q"`}},
+		{[]string{"deprecated endpoint"}, []string{"deprecated endpoint"}},
+		{[]string{`299 - unquoted`}, []string{`299 - unquoted`}},
+	}
+	for _, tc := range cases {
+		h := http.Header{}
+		for _, v := range tc.values {
+			h.Add("Warning", v)
+		}
+		if got := parseWarnings(h); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseWarnings(%q) = %q, want %q", tc.values, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryInitialBackoff is the delay before the first retry when
+	// RetryConfig.InitialBackoff is not set.
+	DefaultRetryInitialBackoff = 200 * time.Millisecond
+
+	// DefaultRetryMaxBackoff caps the retry delay when
+	// RetryConfig.MaxBackoff is not set.
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+// RetryConfig configures the automatic retries enabled with WithRetry.
+//
+// A failed attempt is retried when it ended with a transport error
+// (connection refused or reset, TLS or timeout errors, see TransportError)
+// or with a 429, 502, 503 or 504 response. The delay before retry n is
+// InitialBackoff * 2^(n-1), capped at MaxBackoff and perturbed by Jitter;
+// a Retry-After header on a 429 or 503 response replaces it.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, first one included.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	// If <= 0, DefaultRetryInitialBackoff is used.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponentially growing delay.
+	// If <= 0, DefaultRetryMaxBackoff is used.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction by which every computed delay is randomly
+	// perturbed, e.g. 0.2 for ±20%. 0 disables jitter; values are capped
+	// at 1. Retry-After delays are not perturbed.
+	Jitter float64
+
+	// RetryNonIdempotent also retries POST and PATCH requests. Only set it
+	// when the server deduplicates them (speech uploads are keyed by
+	// session and chunk, for instance): a request that failed with a
+	// transport error may have been applied nonetheless.
+	RetryNonIdempotent bool
+}
+
+// WithRetry makes the client retry failed non-streaming requests as
+// described by cfg. Only idempotent requests (GET, HEAD, PUT, DELETE,
+// such as the snapshot, updates, status and wallet verify calls) are
+// retried unless cfg.RetryNonIdempotent is set. Streams have their own
+// reconnection settings and are not affected.
+//
+// Retries stop when ctx is done: a delay is never waited for past the
+// ctx deadline, and the last error is returned instead. Each retry
+// consumes one unit of the ctx retry budget, if any (see WithRetryBudget),
+// and is reported to the Logger and Metrics. Requests whose body cannot
+// be replayed (streamed audio or text) are not retried; their error
+// then also matches ErrBodyNotReplayable.
+//
+// Without this option requests are attempted once.
+func WithRetry(cfg RetryConfig) Option {
+	return func(c *Client) error {
+		if cfg.MaxAttempts < 2 {
+			c.retry = nil
+			return nil
+		}
+		if cfg.InitialBackoff <= 0 {
+			cfg.InitialBackoff = DefaultRetryInitialBackoff
+		}
+		if cfg.MaxBackoff <= 0 {
+			cfg.MaxBackoff = DefaultRetryMaxBackoff
+		}
+		if cfg.MaxBackoff < cfg.InitialBackoff {
+			cfg.MaxBackoff = cfg.InitialBackoff
+		}
+		cfg.Jitter = min(max(cfg.Jitter, 0), 1)
+		c.retry = &cfg
+		return nil
+	}
+}
+
+// doRetry is doRaw with the retries configured by WithRetry.
+func (c *Client) doRetry(req *http.Request) ([]byte, int, error) {
+	cfg := c.retry
+	retryable := cfg.RetryNonIdempotent || isIdempotentMethod(req.Method)
+
+	for attempt := 1; ; attempt++ {
+		data, status, err := c.doOnce(req)
+		if err == nil || !retryable || attempt >= cfg.MaxAttempts {
+			return data, status, err
+		}
+
+		ctx := req.Context()
+		delay, ok := cfg.delay(attempt, err)
+		if !ok || ctx.Err() != nil {
+			return nil, 0, err
+		}
+		if deadline, has := ctx.Deadline(); has && time.Now().Add(delay).After(deadline) {
+			return nil, 0, err
+		}
+
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return nil, 0, fmt.Errorf("%w; not retried: %w", err, rewindErr)
+		}
+		if budgetErr := consumeRetry(ctx); budgetErr != nil {
+			closeRequestBody(next)
+			return nil, 0, fmt.Errorf("%w; not retried: %w", err, budgetErr)
+		}
+		c.reportRetry(newRetryEvent(c.endpointOf(req), attempt, err, delay))
+
+		if waitErr := waitOrCancel(ctx, delay); waitErr != nil {
+			closeRequestBody(next)
+			return nil, 0, err
+		}
+		req = next
+	}
+}
+
+// delay returns the wait before retrying after attempt failed with err,
+// or false when err is not retryable.
+func (cfg *RetryConfig) delay(attempt int, err error) (time.Duration, bool) {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			if apiErr.RetryAfter > 0 {
+				return apiErr.RetryAfter, true
+			}
+		case http.StatusBadGateway, http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+	case errors.As(err, new(*TransportError)):
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	d := cfg.InitialBackoff
+	for i := 1; i < attempt && d < cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, cfg.MaxBackoff)
+	if cfg.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + cfg.Jitter*(2*rand.Float64()-1)))
+	}
+	return d, true
+}
+
+// isIdempotentMethod reports whether requests with method may be sent
+// again without changing their effect.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// endpointOf returns the API path of req relative to the base URL, as
+// reported in RetryEvent.Endpoint.
+func (c *Client) endpointOf(req *http.Request) string {
+	base := c.normalizedBaseURL().Path
+	if p := strings.TrimPrefix(req.URL.Path, base); p != req.URL.Path && strings.HasPrefix(p, "/") {
+		return p
+	}
+	return req.URL.Path
+}
@@ -0,0 +1,57 @@
+package manaxclient
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// retryBudgetKey is the context key under which WithRetryBudget stores
+// the shared *retryBudget.
+type retryBudgetKey struct{}
+
+// retryBudget counts the retries still allowed for a call chain.
+type retryBudget struct {
+	remaining atomic.Int64
+}
+
+// WithRetryBudget returns a copy of ctx carrying a budget of n retries
+// shared by every client call made with it (or with a context derived
+// from it).
+//
+// Each additional attempt the client makes on its own — stream
+// reconnections, retried requests, credential refreshes — consumes one
+// unit. Once the budget is spent, the next such attempt fails with an
+// error matching ErrRetryBudgetExhausted instead of being issued. First
+// attempts are never counted, so n = 0 disables automatic retries for the
+// chain. Contexts without a budget are not limited.
+func WithRetryBudget(ctx context.Context, n int) context.Context {
+	if n < 0 {
+		n = 0
+	}
+	b := &retryBudget{}
+	b.remaining.Store(int64(n))
+	return context.WithValue(ctx, retryBudgetKey{}, b)
+}
+
+// RetryBudgetRemaining returns the number of retries left in the budget
+// attached to ctx by WithRetryBudget. ok is false when ctx has no budget.
+func RetryBudgetRemaining(ctx context.Context) (n int, ok bool) {
+	b, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if b == nil {
+		return 0, false
+	}
+	return int(max(b.remaining.Load(), 0)), true
+}
+
+// consumeRetry takes one unit from the retry budget of ctx, if any.
+// It returns ErrRetryBudgetExhausted when the budget is already spent.
+func consumeRetry(ctx context.Context) error {
+	b, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if b == nil {
+		return nil
+	}
+	if b.remaining.Add(-1) < 0 {
+		return ErrRetryBudgetExhausted
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRetryBudget_CapsCombinedRetries verifies that a single budget is
+// shared by successive calls: reconnections made by ManagedMatchesStream
+// and WaitForMatch together never exceed n.
+func TestRetryBudget_CapsCombinedRetries(t *testing.T) {
+	var streams int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/matches/items/snapshot":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"proId":"p_123","items":[]}`))
+		case "/api/matches/items/stream":
+			atomic.AddInt32(&streams, 1)
+			// Close immediately without data: every connection forces
+			// a reconnection.
+			w.Header().Set("Content-Type", "text/event-stream")
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = WithRetryBudget(ctx, 3)
+
+	store := NewMemoryCursorStore()
+	_ = store.SaveCursor(ctx, matchesCursorKey("p_123", MatchingDirectionOffer), Cursor{UpdatedUTC: time.Now(), ID: 1})
+	policy := ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	items, errs := client.ManagedMatchesStream(ctx, "p_123", MatchingDirectionOffer, MatchesFilter{}, store, policy)
+	for range items {
+	}
+	if err := <-errs; !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted from ManagedMatchesStream, got %v", err)
+	}
+	if n := atomic.LoadInt32(&streams); n != 4 {
+		t.Fatalf("expected 1 attempt + 3 retries, got %d connections", n)
+	}
+
+	// The budget is spent: a later call in the same chain gets its first
+	// attempt but no reconnection.
+	_, err := client.WaitForMatch(ctx, "p_123", MatchingDirectionOffer, func(MatchItem) bool { return false },
+		PollOrStreamOptions{PollInterval: time.Millisecond})
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted from WaitForMatch, got %v", err)
+	}
+	if n := atomic.LoadInt32(&streams); n != 5 {
+		t.Fatalf("expected a single WaitForMatch connection, got %d total", n)
+	}
+	if left, ok := RetryBudgetRemaining(ctx); !ok || left != 0 {
+		t.Fatalf("expected empty budget, got %d ok=%v", left, ok)
+	}
+}
+
+// TestRetryBudget_Absent verifies that contexts without a budget are not
+// limited.
+func TestRetryBudget_Absent(t *testing.T) {
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		if err := consumeRetry(ctx); err != nil {
+			t.Fatalf("unexpected error without budget: %v", err)
+		}
+	}
+	if _, ok := RetryBudgetRemaining(ctx); ok {
+		t.Fatalf("expected no budget on background context")
+	}
+}
@@ -0,0 +1,174 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithRetry verifies that idempotent requests are retried on 503 and
+// on a transport error, and that zero-config clients are unchanged.
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+		case 2:
+			// Break the connection with a malformed answer.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_, _ = conn.Write([]byte("garbage\r\n\r\n"))
+				conn.Close()
+			}
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true,"found":true}`))
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	if _, err := client.GetSpeechStatusByID(context.Background(), 1); err == nil {
+		t.Fatalf("expected the 503 to fail without WithRetry")
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single attempt without WithRetry, got %d", calls)
+	}
+
+	calls = 0
+	metrics := &recordingMetrics{}
+	client = newClientWithOptions(t, server.URL,
+		WithRetry(RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}), WithMetrics(metrics))
+	st, err := client.GetSpeechStatusByID(context.Background(), 1)
+	if err != nil || !st.Found {
+		t.Fatalf("expected success after retries, got %+v, %v", st, err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if len(metrics.retries) != 2 || metrics.retries[0].StatusCode != http.StatusServiceUnavailable ||
+		metrics.retries[0].Endpoint != "/api/speech/status" || metrics.retries[1].Attempt != 2 || metrics.retries[1].StatusCode != 0 {
+		t.Fatalf("unexpected retry events: %+v", metrics.retries)
+	}
+}
+
+// TestWithRetry_NonIdempotent verifies that POST requests are only
+// retried when explicitly opted in.
+func TestWithRetry_NonIdempotent(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	in := UploadSpeechTextRequest{ProID: "p_123", SessionID: "s_1", Text: "hello"}
+	cfg := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	client := newClientWithOptions(t, server.URL, WithRetry(cfg))
+	if _, err := client.UploadSpeechText(context.Background(), in); err == nil || calls != 1 {
+		t.Fatalf("expected the POST not to be retried, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	cfg.RetryNonIdempotent = true
+	client = newClientWithOptions(t, server.URL, WithRetry(cfg))
+	if _, err := client.UploadSpeechText(context.Background(), in); err != nil || calls != 2 {
+		t.Fatalf("expected the POST to be retried once, got err=%v calls=%d", err, calls)
+	}
+}
+
+// TestWithRetry_BudgetExhaustedClosesBody verifies that a retry stopped by
+// an empty budget does not leak the body recreated for it.
+func TestWithRetry_BudgetExhaustedClosesBody(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	cfg := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryNonIdempotent: true}
+	client := newClientWithOptions(t, server.URL, WithRetry(cfg))
+	in := UploadSpeechTextRequest{ProID: "p_123", SessionID: "s_1", Text: strings.Repeat("x", streamBodyThreshold+1)}
+	ctx := WithRetryBudget(context.Background(), 0)
+
+	// Warm up the keep-alive connection so its goroutines are counted.
+	_, _ = client.UploadSpeechText(ctx, in)
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		if _, err := client.UploadSpeechText(ctx, in); !errors.Is(err, ErrRetryBudgetExhausted) {
+			t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+		}
+	}
+	waitGoroutines(t, before)
+}
+
+// TestWithRetry_RetryAfterDeadline verifies that a Retry-After delay
+// ending past the ctx deadline returns the error instead of sleeping.
+func TestWithRetry_RetryAfterDeadline(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, `{"error":"slow down"}`, http.StatusTooManyRequests)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	client := newClientWithOptions(t, server.URL, WithRetry(RetryConfig{MaxAttempts: 5}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetSpeechStatusByID(ctx, 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected an immediate return, got calls=%d after %v", calls, time.Since(start))
+	}
+}
+
+// TestRetryConfig_Delay verifies the backoff sequence, the Retry-After
+// override and the errors that are not retried.
+func TestRetryConfig_Delay(t *testing.T) {
+	cfg := &RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	unavailable := &APIError{StatusCode: http.StatusBadGateway}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 4: 300 * time.Millisecond} {
+		if d, ok := cfg.delay(attempt, unavailable); !ok || d != want {
+			t.Fatalf("attempt %d: expected %v, got %v (ok=%v)", attempt, want, d, ok)
+		}
+	}
+
+	if d, ok := cfg.delay(1, &APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 2 * time.Second}); !ok || d != 2*time.Second {
+		t.Fatalf("expected Retry-After to win, got %v (ok=%v)", d, ok)
+	}
+	for _, err := range []error{
+		&APIError{StatusCode: http.StatusBadRequest},
+		&APIError{StatusCode: http.StatusInternalServerError},
+		&TransportError{Err: context.Canceled},
+		errors.New("decode JSON response"),
+	} {
+		if _, ok := cfg.delay(1, err); ok {
+			t.Fatalf("expected %v not to be retried", err)
+		}
+	}
+}
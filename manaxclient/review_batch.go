@@ -0,0 +1,131 @@
+package manaxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ReviewStatusUpdate is one entry of a PatchFactReviewStatusBatch call.
+type ReviewStatusUpdate struct {
+	// ID is the fact id (required, > 0).
+	ID int64 `json:"id"`
+
+	// ReviewStatus is the new status ("ok", "not", or "" to clear).
+	ReviewStatus string `json:"reviewStatus"`
+}
+
+// ReviewStatusResult is the outcome of one ReviewStatusUpdate.
+type ReviewStatusResult struct {
+	// ID is the fact id the result refers to.
+	ID int64 `json:"id"`
+
+	// Code and Reason have the meaning of the PatchReviewStatusResponse
+	// fields of the same name.
+	Code   string  `json:"code"`
+	Reason *string `json:"reason"`
+
+	// Err is the error of the per-item request for this id when the
+	// client fell back to one request per update; Code is then empty.
+	// It is never set from the batch endpoint's answer.
+	Err error `json:"-"`
+}
+
+// PatchFactReviewStatusBatch applies several review status updates to the
+// facts of proID with a single
+//
+//	PATCH /api/facts/items/review-status?proId=...
+//
+// whose JSON body is the array of updates ([{"id":1,"reviewStatus":"ok"},
+// ...]). The server answers with an array of per-id results.
+//
+// Servers without the batch endpoint answer 404 or 405; the updates are
+// then sent one by one with PatchFactReviewStatus, in order, and a failed
+// update is reported in the Err field of its result instead of stopping
+// the others. Only cancellation of ctx ends the fallback early, returning
+// the results so far with the context error.
+//
+// An empty updates slice returns no results without contacting the
+// server.
+func (c *Client) PatchFactReviewStatusBatch(
+	ctx context.Context,
+	proID string,
+	updates []ReviewStatusUpdate,
+) ([]ReviewStatusResult, error) {
+	ctx = withOperation(ctx, "PatchFactReviewStatusBatch")
+	proID = c.resolveProID(ctx, proID)
+	if proID == "" {
+		return nil, errors.New("PatchFactReviewStatusBatch: proID must not be empty")
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	body := make([]ReviewStatusUpdate, len(updates))
+	for i, u := range updates {
+		if u.ID <= 0 {
+			return nil, fmt.Errorf("PatchFactReviewStatusBatch: updates[%d]: id must be > 0", i)
+		}
+		body[i] = ReviewStatusUpdate{ID: u.ID, ReviewStatus: encodeReviewStatus(u.ReviewStatus)}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal review status updates: %w", err)
+	}
+
+	q := url.Values{}
+	q.Set("proId", proID)
+
+	req, err := c.newRequest(ctx, http.MethodPatch, "/api/facts/items/review-status", q, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	c.applyHeaders(req, h)
+
+	var out []ReviewStatusResult
+	if err := c.doJSON(req, &out); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) &&
+			(apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+			return c.patchFactReviewStatusEach(ctx, proID, body)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// patchFactReviewStatusEach is the per-item fallback of
+// PatchFactReviewStatusBatch.
+func (c *Client) patchFactReviewStatusEach(
+	ctx context.Context,
+	proID string,
+	updates []ReviewStatusUpdate,
+) ([]ReviewStatusResult, error) {
+	out := make([]ReviewStatusResult, 0, len(updates))
+	for _, u := range updates {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		resp, err := c.patchFactReviewStatus(ctx, "PatchFactReviewStatusBatch", PatchFactReviewStatusRequest{
+			ProID:        proID,
+			ID:           u.ID,
+			ReviewStatus: u.ReviewStatus,
+		})
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return out, ctxErr
+			}
+			out = append(out, ReviewStatusResult{ID: u.ID, Err: err})
+			continue
+		}
+		out = append(out, ReviewStatusResult{ID: u.ID, Code: resp.Code, Reason: resp.Reason})
+	}
+	return out, nil
+}
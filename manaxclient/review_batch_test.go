@@ -0,0 +1,125 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// TestPatchFactReviewStatusBatch verifies that all updates are sent in a
+// single request and that the per-id results are decoded.
+func TestPatchFactReviewStatusBatch(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/facts/items/review-status" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("proId"); got != "p_123" {
+			t.Errorf("unexpected proId: %q", got)
+		}
+		var body []ReviewStatusUpdate
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request failed: %v", err)
+		}
+		if len(body) != 2 || body[0] != (ReviewStatusUpdate{1, "ok"}) || body[1] != (ReviewStatusUpdate{2, "not"}) {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":1,"code":"ok"},{"id":2,"code":"not_found","reason":"no such fact"}]`))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	res, err := client.PatchFactReviewStatusBatch(context.Background(), "p_123", []ReviewStatusUpdate{
+		{ID: 1, ReviewStatus: "ok"},
+		{ID: 2, ReviewStatus: " not "},
+	})
+	if err != nil {
+		t.Fatalf("PatchFactReviewStatusBatch failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single request, got %d", calls)
+	}
+	if len(res) != 2 || res[0].ID != 1 || res[0].Code != "ok" ||
+		res[1].Code != "not_found" || res[1].Reason == nil || *res[1].Reason != "no such fact" {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+}
+
+// TestPatchFactReviewStatusBatch_Fallback verifies that a 405 from the
+// batch endpoint falls back to one request per update and that a failed
+// update is reported in its result without stopping the others.
+func TestPatchFactReviewStatusBatch_Fallback(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/facts/items/review-status":
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+		case "/api/facts/items/2/review-status":
+			http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	res, err := client.PatchFactReviewStatusBatch(context.Background(), "p_123", []ReviewStatusUpdate{
+		{ID: 1, ReviewStatus: "ok"},
+		{ID: 2, ReviewStatus: "ok"},
+		{ID: 3, ReviewStatus: "not"},
+	})
+	if err != nil {
+		t.Fatalf("PatchFactReviewStatusBatch failed: %v", err)
+	}
+	want := []string{
+		"/api/facts/items/review-status",
+		"/api/facts/items/1/review-status",
+		"/api/facts/items/2/review-status",
+		"/api/facts/items/3/review-status",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("expected requests %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("expected requests %v, got %v", want, paths)
+		}
+	}
+
+	if len(res) != 3 || res[0].Code != "ok" || res[2].Code != "ok" || res[2].ID != 3 {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+	var apiErr *APIError
+	if res[1].ID != 2 || res[1].Code != "" || !errors.As(res[1].Err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected per-item error for id 2, got %+v", res[1])
+	}
+}
+
+// TestPatchFactReviewStatusBatch_Error verifies that other batch failures
+// are returned as-is without falling back.
+func TestPatchFactReviewStatusBatch_Error(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"error":"bad"}`, http.StatusBadRequest)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	_, err := client.PatchFactReviewStatusBatch(context.Background(), "p_123", []ReviewStatusUpdate{{ID: 1, ReviewStatus: "ok"}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no fallback requests, got %d calls", calls)
+	}
+}
@@ -0,0 +1,155 @@
+package manaxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SpeechStatusQuery identifies one speech chunk for GetSpeechStatus,
+// either by its numeric row ID or by its composite key (ProID, SessionID,
+// ChunkIndex). Exactly one of the two must be set.
+type SpeechStatusQuery struct {
+	// ID is the numeric row id (see GetSpeechStatusByID); 0 means unset.
+	ID int64
+
+	// ProID, SessionID and ChunkIndex form the composite key (see
+	// GetSpeechStatusByKey). The key is set when SessionID is non-empty;
+	// ProID is optional and ChunkIndex may be 0.
+	ProID      string
+	SessionID  string
+	ChunkIndex int
+}
+
+// GetSpeechStatus looks up a speech chunk by ID or by composite key,
+// calling GetSpeechStatusByID or GetSpeechStatusByKey accordingly.
+//
+// A query setting both the ID and the key is rejected rather than
+// resolved in favour of one of them, as is a query setting neither.
+func (c *Client) GetSpeechStatus(ctx context.Context, in SpeechStatusQuery) (*SpeechStatusResponse, error) {
+	hasID := in.ID != 0
+	hasKey := strings.TrimSpace(in.SessionID) != ""
+	switch {
+	case hasID && hasKey:
+		return nil, errors.New("GetSpeechStatus: query sets both ID and SessionID; set exactly one")
+	case hasID:
+		return c.GetSpeechStatusByID(ctx, in.ID)
+	case hasKey:
+		return c.GetSpeechStatusByKey(ctx, in.ProID, in.SessionID, in.ChunkIndex)
+	default:
+		return nil, errors.New("GetSpeechStatus: query sets neither ID nor SessionID")
+	}
+}
+
+// GetSessionSpeechStatus returns the status of every chunk of a speech
+// session, ordered by the server (normally by chunk index).
+//
+// It first asks for the whole session with a single
+//
+//	GET /api/speech/status?proId=...&sessionId=...
+//
+// without chunkIndex, to which a supporting server answers with a JSON
+// array of SpeechStatusResponse (or an object holding it as "items").
+// A server that requires chunkIndex answers 400, or a single status
+// object; the client then falls back to GetSpeechStatusByKey for chunk
+// indexes 0, 1, 2, ... until a chunk is not found, so the fallback stops
+// at the first gap in the session.
+//
+// An empty result means the session has no chunks. proID is optional, as
+// for GetSpeechStatusByKey.
+func (c *Client) GetSessionSpeechStatus(
+	ctx context.Context,
+	proID string,
+	sessionID string,
+) ([]SpeechStatusResponse, error) {
+	ctx = withOperation(ctx, "GetSessionSpeechStatus")
+	proID = c.resolveProID(ctx, proID)
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" {
+		return nil, errors.New("GetSessionSpeechStatus: sessionID must not be empty")
+	}
+
+	q := url.Values{}
+	if proID != "" {
+		q.Set("proId", proID)
+	}
+	q.Set("sessionId", sessionID)
+
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/speech/status", q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.applyHeaders(req, nil)
+
+	data, _, err := c.doRaw(req)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return c.getSessionSpeechStatusEach(ctx, proID, sessionID)
+		}
+		return nil, err
+	}
+
+	items, ok, err := decodeSessionStatus(data)
+	if err != nil {
+		return nil, fmt.Errorf("GetSessionSpeechStatus: %w", err)
+	}
+	if !ok {
+		return c.getSessionSpeechStatusEach(ctx, proID, sessionID)
+	}
+	return items, nil
+}
+
+// decodeSessionStatus decodes a session-wide status answer. ok is false
+// when data is a single status object, i.e. the server ignored the
+// session-wide query.
+func decodeSessionStatus(data []byte) (items []SpeechStatusResponse, ok bool, err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, false, fmt.Errorf("decode JSON response: %w", err)
+		}
+		return items, true, nil
+	}
+
+	var wrapped struct {
+		Items *[]SpeechStatusResponse `json:"items"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, false, fmt.Errorf("decode JSON response: %w", err)
+	}
+	if wrapped.Items == nil {
+		return nil, false, nil
+	}
+	return *wrapped.Items, true, nil
+}
+
+// getSessionSpeechStatusEach is the per-chunk fallback of
+// GetSessionSpeechStatus.
+func (c *Client) getSessionSpeechStatusEach(
+	ctx context.Context,
+	proID string,
+	sessionID string,
+) ([]SpeechStatusResponse, error) {
+	var out []SpeechStatusResponse
+	for i := 0; ; i++ {
+		st, err := c.GetSpeechStatusByKey(ctx, proID, sessionID, i)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				return out, nil
+			}
+			return nil, fmt.Errorf("GetSessionSpeechStatus: chunk %d: %w", i, err)
+		}
+		if !st.Found {
+			return out, nil
+		}
+		out = append(out, *st)
+	}
+}
@@ -0,0 +1,123 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// TestGetSessionSpeechStatus verifies the session-wide query, in both the
+// array and the wrapped form, with a single request.
+func TestGetSessionSpeechStatus(t *testing.T) {
+	for _, body := range []string{
+		`[{"ok":true,"found":true,"sessionId":"s_1","chunkIndex":0,"asrStatus":"ok"},
+		  {"ok":true,"found":true,"sessionId":"s_1","chunkIndex":1,"asrStatus":"pending"}]`,
+		`{"ok":true,"items":[{"ok":true,"found":true,"sessionId":"s_1","chunkIndex":0,"asrStatus":"ok"},
+		  {"ok":true,"found":true,"sessionId":"s_1","chunkIndex":1,"asrStatus":"pending"}]}`,
+	} {
+		calls := 0
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			q := r.URL.Query()
+			if r.URL.Path != "/api/speech/status" || q.Get("proId") != "p_123" || q.Get("sessionId") != "s_1" || q.Has("chunkIndex") {
+				t.Errorf("unexpected request: %s", r.URL.String())
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}
+
+		client, server := newTestClient(t, handler)
+		got, err := client.GetSessionSpeechStatus(context.Background(), "p_123", "s_1")
+		server.Close()
+		if err != nil {
+			t.Fatalf("GetSessionSpeechStatus failed: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected a single request, got %d", calls)
+		}
+		if len(got) != 2 || got[0].ChunkIndex != 0 || got[1].AsrStatus != "pending" {
+			t.Fatalf("unexpected statuses: %+v", got)
+		}
+	}
+}
+
+// TestGetSessionSpeechStatus_Fallback verifies that a server requiring
+// chunkIndex is queried chunk by chunk until a chunk is not found, with
+// the proID resolved from the auth profile on every request.
+func TestGetSessionSpeechStatus_Fallback(t *testing.T) {
+	var queried []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("proId") != "p_auth" {
+			t.Errorf("expected proId from auth, got %s", r.URL.String())
+		}
+		if !q.Has("chunkIndex") {
+			http.Error(w, `{"error":"chunkIndex is required"}`, http.StatusBadRequest)
+			return
+		}
+		queried = append(queried, q.Get("chunkIndex"))
+		idx, _ := strconv.Atoi(q.Get("chunkIndex"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SpeechStatusResponse{Ok: true, Found: idx < 3, SessionID: "s_1", ChunkIndex: idx})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, WithDefaultProIDFromAuth())
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+	client.SetAuth("p_auth", "token")
+
+	got, err := client.GetSessionSpeechStatus(context.Background(), "", "s_1")
+	if err != nil {
+		t.Fatalf("GetSessionSpeechStatus failed: %v", err)
+	}
+	if len(got) != 3 || got[2].ChunkIndex != 2 {
+		t.Fatalf("expected chunks 0..2, got %+v", got)
+	}
+	if len(queried) != 4 {
+		t.Fatalf("expected 4 per-chunk requests, got %v", queried)
+	}
+}
+
+// TestGetSpeechStatus verifies that a query is routed by ID or by key and
+// that ambiguous or empty queries fail without a request.
+func TestGetSpeechStatus(t *testing.T) {
+	var queries []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"found":true}`))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := client.GetSpeechStatus(ctx, SpeechStatusQuery{ID: 42}); err != nil {
+		t.Fatalf("ID query failed: %v", err)
+	}
+	if _, err := client.GetSpeechStatus(ctx, SpeechStatusQuery{ProID: "p_123", SessionID: "s_1", ChunkIndex: 0}); err != nil {
+		t.Fatalf("key query failed: %v", err)
+	}
+	if len(queries) != 2 || queries[0] != "id=42" || queries[1] != "chunkIndex=0&proId=p_123&sessionId=s_1" {
+		t.Fatalf("unexpected queries: %q", queries)
+	}
+
+	for _, q := range []SpeechStatusQuery{
+		{ID: 42, SessionID: "s_1"},
+		{ProID: "p_123", ChunkIndex: 1},
+	} {
+		if _, err := client.GetSpeechStatus(ctx, q); err == nil {
+			t.Fatalf("expected %+v to be rejected", q)
+		}
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected invalid queries not to reach the server, got %q", queries)
+	}
+}
@@ -0,0 +1,233 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SpeechSessionOptions configures a SpeechSession.
+type SpeechSessionOptions struct {
+	// SampleRate is sent with every chunk; 0 lets the server auto-detect.
+	SampleRate int
+
+	// FileName is the multipart filename used for every chunk.
+	// If empty, UploadSpeechAudio's default ("audio") is used.
+	FileName string
+
+	// VerifyContiguous makes AddChunkAt reject an explicit index that
+	// would leave a gap after the last uploaded chunk (for example 0, 1, 3).
+	// Re-uploading an index that was already sent is still allowed.
+	VerifyContiguous bool
+}
+
+// SpeechSession uploads the audio chunks of one logical recording
+// (proID + sessionID) and keeps track of the next chunk index.
+//
+// Chunks are uploaded one at a time: concurrent calls on the same session
+// are serialized so that indexes are assigned in call order.
+//
+// Every upload runs under both the caller's context and the session's own
+// context, so Cancel aborts all in-flight and queued uploads at once.
+type SpeechSession struct {
+	client    *Client
+	proID     string
+	sessionID string
+	opt       SpeechSessionOptions
+
+	// ctx is cancelled by Cancel with ErrSessionCancelled as the cause.
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu        sync.Mutex
+	nextIndex int
+
+	// closed is "aborted" or "finalized" once no more chunks may be added.
+	closed string
+}
+
+// NewSpeechSession returns a SpeechSession for proID and sessionID whose
+// first chunk index is 0.
+func (c *Client) NewSpeechSession(proID, sessionID string, opt SpeechSessionOptions) (*SpeechSession, error) {
+	proID = c.resolveProID(context.Background(), proID)
+	sessionID = strings.TrimSpace(sessionID)
+	if proID == "" {
+		return nil, errors.New("NewSpeechSession: proID must not be empty")
+	}
+	if sessionID == "" {
+		return nil, errors.New("NewSpeechSession: sessionID must not be empty")
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &SpeechSession{
+		client:    c,
+		proID:     proID,
+		sessionID: sessionID,
+		opt:       opt,
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+// DeleteSpeechSession issues DELETE /api/speech/sessions/{sessionId}?proId=...
+// to discard an abandoned session together with the chunks already
+// uploaded for it.
+//
+// A 404 response means the session does not exist (or is already gone)
+// and is returned as an *APIError, or as an error matching ErrNotFound
+// with WithNotFoundAsError. With WithDeleteNotFoundOK it yields nil
+// instead, which makes the call idempotent.
+func (c *Client) DeleteSpeechSession(ctx context.Context, proID, sessionID string) error {
+	ctx = withOperation(ctx, "DeleteSpeechSession")
+	proID = c.resolveProID(ctx, proID)
+	sessionID = strings.TrimSpace(sessionID)
+	if proID == "" {
+		return errors.New("DeleteSpeechSession: proID must not be empty")
+	}
+	if sessionID == "" {
+		return errors.New("DeleteSpeechSession: sessionID must not be empty")
+	}
+
+	q := url.Values{}
+	q.Set("proId", proID)
+
+	endpoint := "/api/speech/sessions/" + url.PathEscape(sessionID)
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint, q, nil)
+	if err != nil {
+		return err
+	}
+
+	c.applyHeaders(req, nil)
+
+	if err := c.doJSON(req, nil); err != nil {
+		var apiErr *APIError
+		if c.deleteNotFoundOK && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return c.mapNotFound("DeleteSpeechSession", err)
+	}
+	return nil
+}
+
+// ProID returns the profile id of the session.
+func (s *SpeechSession) ProID() string { return s.proID }
+
+// SessionID returns the session id.
+func (s *SpeechSession) SessionID() string { return s.sessionID }
+
+// NextChunkIndex returns the index AddChunk will use for the next chunk.
+func (s *SpeechSession) NextChunkIndex() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextIndex
+}
+
+// AddChunk uploads audio as the next chunk of the session. The index is
+// advanced only when the upload succeeds, so a failed chunk can simply be
+// retried with another AddChunk call.
+func (s *SpeechSession) AddChunk(ctx context.Context, audio io.Reader) (*SpeechUploadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.upload(ctx, s.nextIndex, audio, false)
+}
+
+// AddChunkAt uploads audio with an explicit chunk index.
+//
+// With SpeechSessionOptions.VerifyContiguous, an index greater than
+// NextChunkIndex returns an error matching ErrChunkIndexGap without
+// contacting the server.
+func (s *SpeechSession) AddChunkAt(ctx context.Context, index int, audio io.Reader) (*SpeechUploadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opt.VerifyContiguous && index > s.nextIndex {
+		return nil, fmt.Errorf("AddChunkAt: %w: got index %d, expected %d", ErrChunkIndexGap, index, s.nextIndex)
+	}
+	return s.upload(ctx, index, audio, false)
+}
+
+// Abort deletes the session and its uploaded chunks on the server (see
+// DeleteSpeechSession). After a successful Abort, AddChunk and AddChunkAt
+// fail without contacting the server.
+func (s *SpeechSession) Abort(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.client.DeleteSpeechSession(ctx, s.proID, s.sessionID); err != nil {
+		return err
+	}
+	s.closed = "aborted"
+	return nil
+}
+
+// Cancel aborts every in-flight and waiting AddChunk, AddChunkAt and
+// Finalize call of the session; they return an error matching
+// ErrSessionCancelled. Later uploads fail the same way without contacting
+// the server. Cancel does not delete anything server-side (see Abort,
+// which still works after Cancel) and may be called from any goroutine,
+// any number of times.
+func (s *SpeechSession) Cancel() {
+	s.cancel(ErrSessionCancelled)
+}
+
+// Finalize tells the server that the session is complete so that it can
+// run the final ASR pass. audio, if non-nil, is uploaded as the last
+// chunk with the final flag; if nil, an empty chunk is sent at
+// NextChunkIndex as a pure end-of-session marker.
+//
+// After a successful Finalize, AddChunk and AddChunkAt fail without
+// contacting the server. See UploadSpeechAudioRequest.Final for the
+// server contract.
+func (s *SpeechSession) Finalize(ctx context.Context, audio io.Reader) (*SpeechUploadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if audio == nil {
+		audio = strings.NewReader("")
+	}
+	resp, err := s.upload(ctx, s.nextIndex, audio, true)
+	if err != nil {
+		return nil, err
+	}
+	s.closed = "finalized"
+	return resp, nil
+}
+
+// upload sends one chunk; s.mu must be held.
+func (s *SpeechSession) upload(ctx context.Context, index int, audio io.Reader, final bool) (*SpeechUploadResponse, error) {
+	if s.closed != "" {
+		return nil, fmt.Errorf("SpeechSession: session %q has been %s", s.sessionID, s.closed)
+	}
+	if s.ctx.Err() != nil {
+		return nil, fmt.Errorf("SpeechSession: session %q: %w", s.sessionID, context.Cause(s.ctx))
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(s.ctx, func() { cancel(context.Cause(s.ctx)) })
+	defer stop()
+
+	resp, err := s.client.UploadSpeechAudio(ctx, UploadSpeechAudioRequest{
+		ProID:      s.proID,
+		SessionID:  s.sessionID,
+		ChunkIndex: index,
+		Audio:      audio,
+		FileName:   s.opt.FileName,
+		SampleRate: s.opt.SampleRate,
+		Final:      final,
+	})
+	if err != nil {
+		if s.ctx.Err() != nil {
+			return nil, fmt.Errorf("SpeechSession: session %q: %w: %w", s.sessionID, context.Cause(s.ctx), err)
+		}
+		return nil, err
+	}
+	if index >= s.nextIndex {
+		s.nextIndex = index + 1
+	}
+	return resp, nil
+}
@@ -0,0 +1,270 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newChunkRecorder returns a handler for /api/speech/upload that records
+// the chunkIndex of every upload.
+func newChunkRecorder(t *testing.T, got *[]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/speech/upload" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm failed: %v", err)
+			return
+		}
+		idx, _ := strconv.Atoi(r.FormValue("chunkIndex"))
+		*got = append(*got, idx)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SpeechUploadResponse{
+			Ok:         true,
+			ProID:      r.FormValue("proId"),
+			SessionID:  r.FormValue("sessionId"),
+			ChunkIndex: idx,
+		})
+	}
+}
+
+// TestSpeechSession_Contiguous verifies that AddChunk assigns consecutive
+// indexes and that matching explicit indexes pass verification.
+func TestSpeechSession_Contiguous(t *testing.T) {
+	var got []int
+	client, server := newTestClient(t, newChunkRecorder(t, &got))
+	defer server.Close()
+
+	s, err := client.NewSpeechSession("p_123", "s_1", SpeechSessionOptions{VerifyContiguous: true})
+	if err != nil {
+		t.Fatalf("NewSpeechSession failed: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := s.AddChunk(ctx, strings.NewReader("audio")); err != nil {
+			t.Fatalf("AddChunk %d failed: %v", i, err)
+		}
+	}
+	if _, err := s.AddChunkAt(ctx, 2, strings.NewReader("audio")); err != nil {
+		t.Fatalf("AddChunkAt(2) failed: %v", err)
+	}
+	// Re-uploading an earlier chunk is not a gap.
+	if _, err := s.AddChunkAt(ctx, 1, strings.NewReader("audio")); err != nil {
+		t.Fatalf("AddChunkAt(1) failed: %v", err)
+	}
+
+	want := []int{0, 1, 2, 1}
+	if len(got) != len(want) {
+		t.Fatalf("expected uploads %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected uploads %v, got %v", want, got)
+		}
+	}
+	if n := s.NextChunkIndex(); n != 3 {
+		t.Fatalf("expected next index 3, got %d", n)
+	}
+}
+
+// TestSpeechSession_Gap verifies that a skipped index is rejected before
+// any request is sent when VerifyContiguous is set, and accepted otherwise.
+func TestSpeechSession_Gap(t *testing.T) {
+	var got []int
+	client, server := newTestClient(t, newChunkRecorder(t, &got))
+	defer server.Close()
+
+	ctx := context.Background()
+	s, _ := client.NewSpeechSession("p_123", "s_1", SpeechSessionOptions{VerifyContiguous: true})
+	for i := 0; i < 2; i++ {
+		if _, err := s.AddChunk(ctx, strings.NewReader("audio")); err != nil {
+			t.Fatalf("AddChunk %d failed: %v", i, err)
+		}
+	}
+
+	_, err := s.AddChunkAt(ctx, 3, strings.NewReader("audio"))
+	if !errors.Is(err, ErrChunkIndexGap) {
+		t.Fatalf("expected ErrChunkIndexGap, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected gap to be rejected client-side, server saw %v", got)
+	}
+	if n := s.NextChunkIndex(); n != 2 {
+		t.Fatalf("expected next index to stay 2, got %d", n)
+	}
+
+	loose, _ := client.NewSpeechSession("p_123", "s_2", SpeechSessionOptions{})
+	if _, err := loose.AddChunkAt(ctx, 5, strings.NewReader("audio")); err != nil {
+		t.Fatalf("expected gap to be allowed without VerifyContiguous, got %v", err)
+	}
+	if n := loose.NextChunkIndex(); n != 6 {
+		t.Fatalf("expected next index 6, got %d", n)
+	}
+}
+
+// TestDeleteSpeechSession verifies the DELETE request and that a later
+// AddChunk on an aborted session fails client-side.
+func TestDeleteSpeechSession(t *testing.T) {
+	deletes := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		if r.URL.Path != "/api/speech/sessions/s_1" || r.URL.Query().Get("proId") != "p_123" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		deletes++
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	s, _ := client.NewSpeechSession("p_123", "s_1", SpeechSessionOptions{})
+	if err := s.Abort(context.Background()); err != nil {
+		t.Fatalf("Abort returned error: %v", err)
+	}
+	if _, err := s.AddChunk(context.Background(), strings.NewReader("audio")); err == nil {
+		t.Fatalf("expected AddChunk to fail after Abort")
+	}
+	if deletes != 1 {
+		t.Fatalf("expected exactly one request, got %d", deletes)
+	}
+}
+
+// TestDeleteSpeechSession_NotFound verifies that 404 is an error by
+// default and nil with WithDeleteNotFoundOK.
+func TestDeleteSpeechSession_NotFound(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"session not found"}`, http.StatusNotFound)
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	err := client.DeleteSpeechSession(context.Background(), "p_123", "s_1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+
+	client = newClientWithOptions(t, server.URL, WithDeleteNotFoundOK())
+	if err := client.DeleteSpeechSession(context.Background(), "p_123", "s_1"); err != nil {
+		t.Fatalf("expected nil for already-gone session, got %v", err)
+	}
+}
+
+// TestSpeechSession_Finalize verifies that the final flag is sent only on
+// the last chunk, that a nil reader sends an empty marker chunk, and that
+// the session rejects further chunks afterwards.
+func TestSpeechSession_Finalize(t *testing.T) {
+	type upload struct {
+		index int
+		final string
+		size  int
+	}
+	var got []upload
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm failed: %v", err)
+			return
+		}
+		idx, _ := strconv.Atoi(r.FormValue("chunkIndex"))
+		size := 0
+		if fh := r.MultipartForm.File["audio"]; len(fh) == 1 {
+			size = int(fh[0].Size)
+		}
+		got = append(got, upload{idx, r.FormValue("final"), size})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx := context.Background()
+	s, _ := client.NewSpeechSession("p_123", "s_1", SpeechSessionOptions{})
+	if _, err := s.AddChunk(ctx, strings.NewReader("audio")); err != nil {
+		t.Fatalf("AddChunk failed: %v", err)
+	}
+	if _, err := s.Finalize(ctx, nil); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	if _, err := s.AddChunk(ctx, strings.NewReader("late")); err == nil {
+		t.Fatalf("expected AddChunk to fail after Finalize")
+	}
+
+	want := []upload{{0, "", 5}, {1, "true", 0}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("expected uploads %+v, got %+v", want, got)
+	}
+
+	// Flagging a chunk that carries audio.
+	got = nil
+	s2, _ := client.NewSpeechSession("p_123", "s_2", SpeechSessionOptions{})
+	if _, err := s2.Finalize(ctx, strings.NewReader("last")); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	if len(got) != 1 || got[0] != (upload{0, "true", 4}) {
+		t.Fatalf("expected final audio chunk, got %+v", got)
+	}
+}
+
+// TestSpeechSession_Cancel verifies that Cancel aborts a slow in-flight
+// upload and a queued one promptly, and that later uploads are refused.
+func TestSpeechSession_Cancel(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+	defer close(release)
+
+	s, _ := client.NewSpeechSession("p_123", "s_1", SpeechSessionOptions{})
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := s.AddChunk(context.Background(), strings.NewReader("audio"))
+			errs <- err
+		}()
+	}
+
+	<-started
+	s.Cancel()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, ErrSessionCancelled) {
+				t.Fatalf("expected ErrSessionCancelled, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("upload did not return after Cancel")
+		}
+	}
+	if len(started) != 0 {
+		t.Fatalf("expected the queued upload not to reach the server")
+	}
+
+	if _, err := s.AddChunk(context.Background(), strings.NewReader("audio")); !errors.Is(err, ErrSessionCancelled) {
+		t.Fatalf("expected ErrSessionCancelled after Cancel, got %v", err)
+	}
+	if n := s.NextChunkIndex(); n != 0 {
+		t.Fatalf("expected next index to stay 0, got %d", n)
+	}
+}
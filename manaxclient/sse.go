@@ -40,7 +40,36 @@ type SSEEvent struct {
 	//   : ping
 	// or:
 	//   : matches-stream-start
+	//
+	// A bare ":" keepalive yields an empty Comment; use IsComment to tell
+	// comment-only events apart from events without fields.
 	Comment string
+
+	// commentOnly is set when the event consisted of comment lines only.
+	commentOnly bool
+}
+
+// IsComment reports whether the event consisted of comment lines only
+// (keepalives, stream markers), including a bare ":" line.
+func (e *SSEEvent) IsComment() bool {
+	return e.commentOnly
+}
+
+// SSEEventMeta carries the SSE framing context of a delivered event:
+// the fields that are not part of the decoded JSON payload.
+//
+// It is passed to metadata-aware handlers such as MatchesStreamMetaHandler.
+type SSEEventMeta struct {
+	// ID is the event's "id:" field, or empty if the server sent none.
+	ID string
+
+	// Retry is the event's raw "retry:" field (milliseconds), if any.
+	Retry string
+
+	// Comments lists the comment-only events (keepalives, stream markers)
+	// received since the previously delivered event, in arrival order.
+	// Only the latest 64 are kept.
+	Comments []string
 }
 
 // sseReader is a low-level incremental parser for Server-Sent Events.
@@ -61,6 +90,10 @@ func newSSEReader(r io.Reader) *sseReader {
 
 // ReadEvent reads the next SSE event from the underlying stream.
 //
+// Fields may appear in any order within an event: "data:" lines sent
+// before (or around) the "event:" line belong to the same event, and the
+// last "event:", "id:" and "retry:" lines win.
+//
 // It returns io.EOF when the stream is closed and no more events
 // are available. Partial events (incomplete frame at EOF) are
 // ignored and io.EOF is returned.
@@ -119,6 +152,7 @@ func (sr *sseReader) ReadEvent() (*SSEEvent, error) {
 			// fields or data. Otherwise ignore inline comments.
 			if !hasFields && !hasData {
 				event.Comment = comment
+				event.commentOnly = true
 			}
 			continue
 		}
@@ -136,14 +170,17 @@ func (sr *sseReader) ReadEvent() (*SSEEvent, error) {
 			value = ""
 		}
 
+		event.commentOnly = false
+
 		switch field {
 		case "event":
 			event.Event = value
 			hasFields = true
 		case "data":
-			if dataBuf.Len() > 0 {
+			if hasData {
 				// Multiple "data:" lines are joined using '\n'
-				// according to the SSE specification.
+				// according to the SSE specification, including
+				// empty ones.
 				dataBuf.WriteByte('\n')
 			}
 			dataBuf.WriteString(value)
@@ -1,6 +1,8 @@
 package manaxclient
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
@@ -68,4 +70,131 @@ func TestSSEReader_CommentOnly(t *testing.T) {
 	if ev.Event != "" || len(ev.Data) != 0 {
 		t.Fatalf("expected no Event/Data for comment-only event, got %#v", ev)
 	}
-}
\ No newline at end of file
+}
+
+// TestSSEReader_BareColonKeepalive verifies that a lone ":" line is a
+// comment-only event with an empty Comment, and that a comment followed
+// by fields is not.
+func TestSSEReader_BareColonKeepalive(t *testing.T) {
+	raw := ":\n\n" +
+		": note\nevent: facts\ndata: {}\n\n"
+
+	r := newSSEReader(strings.NewReader(raw))
+
+	ev, err := r.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent returned error: %v", err)
+	}
+	if !ev.IsComment() || ev.Comment != "" {
+		t.Fatalf("expected empty comment-only event, got %#v", ev)
+	}
+
+	ev, err = r.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent returned error: %v", err)
+	}
+	if ev.IsComment() || ev.Event != "facts" {
+		t.Fatalf("expected facts event, got %#v", ev)
+	}
+}
+
+// TestSSEReader_DataBeforeEvent verifies that fields are paired into one
+// event regardless of their order, including data lines on both sides of
+// the event line and empty data lines.
+func TestSSEReader_DataBeforeEvent(t *testing.T) {
+	raw := "data: {\"foo\":1}\n" +
+		"event: facts\n" +
+		"\n" +
+		"data: a\n" +
+		"id: 7\n" +
+		"event: matches\n" +
+		"data: b\n" +
+		"\n" +
+		"data:\n" +
+		"data: c\n" +
+		"event: facts\n" +
+		"\n"
+
+	r := newSSEReader(strings.NewReader(raw))
+
+	want := []struct{ event, data, id string }{
+		{"facts", `{"foo":1}`, ""},
+		{"matches", "a\nb", "7"},
+		{"facts", "\nc", ""},
+	}
+	for i, w := range want {
+		ev, err := r.ReadEvent()
+		if err != nil {
+			t.Fatalf("event %d: ReadEvent returned error: %v", i, err)
+		}
+		if ev.Event != w.event || string(ev.Data) != w.data || ev.ID != w.id {
+			t.Fatalf("event %d: expected %+v, got event=%q data=%q id=%q", i, w, ev.Event, ev.Data, ev.ID)
+		}
+	}
+	if _, err := r.ReadEvent(); err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+}
+
+// writeSSEEvent encodes v as JSON and writes it to w as a single SSE
+// event with the given name, terminated by a blank line.
+func writeSSEEvent(t *testing.T, w io.Writer, event string, v any) {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal SSE payload failed: %v", err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		t.Fatalf("write SSE event failed: %v", err)
+	}
+}
+
+// FuzzSSEReader feeds arbitrary bytes to sseReader and checks that it
+// never panics, always terminates with io.EOF on a finite input, and
+// never returns more events than the input has lines.
+func FuzzSSEReader(f *testing.F) {
+	seeds := []string{
+		"event: facts\ndata: {\"foo\":1}\n\n",
+		"event: matches\ndata: {\"items\":[]}\nid: 42\nretry: 3000\n\n",
+		"data: line1\ndata: line2\n\n",
+		": ping\n\n",
+		": matches-stream-start\n\nevent: matches\ndata: {}\n\n",
+		":\n\n",
+		":",
+		"data\n\n",
+		"data:\n\n",
+		"event: facts\r\ndata: {}\r\n\r\n",
+		"\r\n\r\n\n\n",
+		"event:facts\ndata:{}",
+		"id\nretry\nevent\n\n",
+		"::::\n:\r\n\n",
+		"data: " + strings.Repeat("x", 70000) + "\n\n",
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := newSSEReader(strings.NewReader(string(data)))
+		maxEvents := strings.Count(string(data), "\n") + 1
+		for n := 0; ; n++ {
+			if n > maxEvents {
+				t.Fatalf("reader returned more than %d events for %d bytes", maxEvents, len(data))
+			}
+			ev, err := r.ReadEvent()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ev == nil {
+				t.Fatalf("nil event without error")
+			}
+			if strings.ContainsAny(ev.Event+ev.ID+ev.Retry+ev.Comment, "\n") {
+				t.Fatalf("field contains a line break: %+v", ev)
+			}
+		}
+	})
+}
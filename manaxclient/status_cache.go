@@ -0,0 +1,111 @@
+package manaxclient
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSpeechStatusCacheSize is the number of entries used by
+	// WithSpeechStatusCache when a non-positive size is passed.
+	DefaultSpeechStatusCacheSize = 256
+
+	// DefaultSpeechStatusCacheTTL is the entry lifetime used by
+	// WithSpeechStatusCache when a non-positive TTL is passed.
+	DefaultSpeechStatusCacheTTL = 30 * time.Second
+)
+
+// isTerminalSpeechStatus reports whether s describes a row whose ASR
+// status can no longer change ("ok" or "error").
+func isTerminalSpeechStatus(s *SpeechStatusResponse) bool {
+	if s == nil || !s.Found {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(s.AsrStatus)) {
+	case "ok", "error":
+		return true
+	}
+	return false
+}
+
+// statusCache is a fixed-size LRU of terminal speech statuses keyed by
+// row id, with a per-entry TTL. It is safe for concurrent use.
+type statusCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+	order *list.List // front = most recently used
+	items map[int64]*list.Element
+}
+
+// statusCacheEntry is the value stored in statusCache.order.
+type statusCacheEntry struct {
+	id      int64
+	status  SpeechStatusResponse
+	expires time.Time
+}
+
+// newStatusCache returns an empty cache, applying the defaults for
+// non-positive size and ttl.
+func newStatusCache(size int, ttl time.Duration) *statusCache {
+	if size <= 0 {
+		size = DefaultSpeechStatusCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultSpeechStatusCacheTTL
+	}
+	return &statusCache{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[int64]*list.Element),
+	}
+}
+
+// get returns a copy of the cached status for id, if present and fresh.
+func (sc *statusCache) get(id int64) (*SpeechStatusResponse, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	el, ok := sc.items[id]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*statusCacheEntry)
+	if !sc.now().Before(e.expires) {
+		sc.order.Remove(el)
+		delete(sc.items, id)
+		return nil, false
+	}
+	sc.order.MoveToFront(el)
+	out := e.status
+	return &out, true
+}
+
+// put stores a copy of s under id if it is terminal; other statuses are
+// ignored so that pending rows are always re-fetched.
+func (sc *statusCache) put(id int64, s *SpeechStatusResponse) {
+	if !isTerminalSpeechStatus(s) {
+		return
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	e := &statusCacheEntry{id: id, status: *s, expires: sc.now().Add(sc.ttl)}
+	if el, ok := sc.items[id]; ok {
+		el.Value = e
+		sc.order.MoveToFront(el)
+		return
+	}
+	sc.items[id] = sc.order.PushFront(e)
+	for sc.order.Len() > sc.size {
+		oldest := sc.order.Back()
+		sc.order.Remove(oldest)
+		delete(sc.items, oldest.Value.(*statusCacheEntry).id)
+	}
+}
@@ -0,0 +1,76 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestSpeechStatusCache verifies that terminal statuses are served from
+// the cache while pending ones are always fetched from the server.
+func TestSpeechStatusCache(t *testing.T) {
+	calls := map[string]int{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		calls[id]++
+		status := "pending"
+		if id == "1" {
+			status = "ok"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SpeechStatusResponse{Ok: true, Found: true, AsrStatus: status})
+	}
+
+	_, server := newTestClient(t, handler)
+	defer server.Close()
+	client, err := NewClientWithOptions(server.URL, WithSpeechStatusCache(8, time.Minute))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		for _, id := range []int64{1, 2} {
+			if _, err := client.GetSpeechStatusByID(context.Background(), id); err != nil {
+				t.Fatalf("GetSpeechStatusByID(%d) returned error: %v", id, err)
+			}
+		}
+	}
+	if calls["1"] != 1 {
+		t.Fatalf("expected terminal status to be fetched once, got %d", calls["1"])
+	}
+	if calls["2"] != 3 {
+		t.Fatalf("expected pending status to be fetched every time, got %d", calls["2"])
+	}
+}
+
+// TestStatusCache_TTLAndEviction verifies expiry and LRU eviction.
+func TestStatusCache_TTLAndEviction(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sc := newStatusCache(2, time.Second)
+	sc.now = func() time.Time { return now }
+
+	done := &SpeechStatusResponse{Found: true, AsrStatus: "error"}
+	sc.put(1, done)
+	sc.put(2, done)
+	sc.get(1) // 1 becomes most recently used
+	sc.put(3, done)
+
+	if _, ok := sc.get(2); ok {
+		t.Fatalf("expected least recently used entry to be evicted")
+	}
+	if _, ok := sc.get(1); !ok {
+		t.Fatalf("expected entry 1 to be cached")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := sc.get(1); ok {
+		t.Fatalf("expected entry to expire after TTL")
+	}
+
+	sc.put(4, &SpeechStatusResponse{Found: true, AsrStatus: "pending"})
+	if _, ok := sc.get(4); ok {
+		t.Fatalf("expected pending status not to be cached")
+	}
+}
@@ -0,0 +1,652 @@
+package manaxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// StreamOptions holds behaviour settings shared by all SSE streams
+// (StreamFacts, StreamMatches). The zero value reproduces the default
+// behaviour of the plain streaming methods.
+type StreamOptions struct {
+	// DedupByID enables id-based deduplication of SSE events. When set,
+	// an event carrying an "id:" field is skipped if its id is less than
+	// or equal to the id of the last successfully handled event.
+	//
+	// Ids are compared numerically when both parse as integers; otherwise
+	// only an exact repeat of the last id is treated as a duplicate.
+	// Events without an id are never skipped.
+	DedupByID bool
+
+	// RejectNonStreaming makes the stream fail with ErrNotStreaming when
+	// the server sends a buffered response instead of a live stream, for
+	// example a misconfigured proxy that sets Content-Length.
+	//
+	// By default such responses are accepted: the buffered events are
+	// processed and the stream terminates cleanly with a nil error once
+	// the body is exhausted.
+	RejectNonStreaming bool
+
+	// MaxEvents, when positive, stops the stream with a nil error right
+	// after the handler has processed the MaxEvents-th event. Comments
+	// and skipped events do not count. 0 means unlimited.
+	MaxEvents int
+
+	// OnStreamReady, if set, is invoked once per connection as soon as
+	// the server is known to be streaming: when a start marker comment
+	// (e.g. ": matches-stream-start") is received, or otherwise right
+	// before the first event is delivered to the handler. It runs
+	// synchronously on the streaming goroutine and must not block.
+	OnStreamReady func()
+
+	// OnComment, if set, is invoked with the text of every comment-only
+	// event (keepalives such as ": ping", stream start/end markers), the
+	// leading colon and surrounding spaces removed, as in
+	// SSEEvent.Comment. It is meant for diagnostics and does not change
+	// how events are processed. It runs synchronously on the streaming
+	// goroutine and must not block.
+	OnComment func(comment string)
+
+	// MaxStreamBytes, when positive, caps the cumulative number of body
+	// bytes read over the lifetime of the stream. Exceeding it terminates
+	// the stream with an error matching ErrStreamTooLarge.
+	//
+	// The limit applies to decompressed bytes: net/http transparently
+	// decodes gzip responses it requested, so a tiny compressed stream
+	// that inflates to gigabytes is still caught. 0 means unlimited.
+	MaxStreamBytes int64
+
+	// RawSink, if set, receives a copy of the raw SSE bytes exactly as
+	// they are read from the response body, before parsing and decoding,
+	// so that a stream can be archived and replayed byte for byte.
+	//
+	// Bytes are copied in read order, not per event: when the stream
+	// stops early (handler error, MaxEvents) the sink may hold part of a
+	// frame read ahead of the last delivered event. Writes happen on the
+	// streaming goroutine and must not block for long.
+	RawSink io.Writer
+
+	// RawSinkErrorFatal makes a RawSink write error terminate the stream
+	// with that error. By default the error is logged (see WithLogger),
+	// the sink is detached for the rest of the connection and decoding
+	// continues unaffected.
+	RawSinkErrorFatal bool
+
+	// IdleTimeout, when positive, terminates the stream with an error
+	// matching ErrStreamIdle if no bytes at all (not even keepalive
+	// comments) arrive for that long, detecting silently dead
+	// connections. 0 disables the check.
+	IdleTimeout time.Duration
+
+	// RecoverHandlerPanics makes the stream recover a panic raised by the
+	// handler, close the connection and return an error matching
+	// ErrHandlerPanic (wrapping the panic value when it is an error)
+	// instead of crashing the program. By default panics propagate.
+	RecoverHandlerPanics bool
+
+	// LastCursor, if set, receives the cursor (CursorUpdatedUTC,
+	// CursorID) of every chunk the handler returned nil for, so that after
+	// the stream ends, cleanly or with an error, it holds the furthest
+	// processed position to resume from. Chunks without a cursor leave it
+	// unchanged. It is written on the streaming goroutine; read it once
+	// the streaming method has returned.
+	// StreamEvents does not use it.
+	LastCursor *Cursor
+
+	// Stats, if set, accumulates handler timings for the stream (see
+	// StreamStats). Read it once the streaming method has returned.
+	Stats *StreamStats
+
+	// HandlerWarnAfter, when positive, logs a warning (see WithLogger)
+	// for every event whose handler ran longer than this. Slow handlers
+	// delay reading the stream and risk a server-side disconnect.
+	HandlerWarnAfter time.Duration
+
+	// HandlerMaxDuration, when positive, terminates the stream with an
+	// error matching ErrHandlerTooSlow once a handler call has taken
+	// longer than this. The handler is not interrupted: the check runs
+	// after it returns.
+	HandlerMaxDuration time.Duration
+
+	// onConnected, if set, is called once the response has been accepted
+	// as an event stream, before anything is read from it.
+	onConnected func()
+
+	// keepComments makes runStream collect comment-only events into
+	// SSEEventMeta.Comments. It is set only for handlers that read the
+	// metadata, so keepalives are not buffered for nothing.
+	keepComments bool
+}
+
+// maxMetaComments bounds SSEEventMeta.Comments: on a stream that sends
+// keepalives but no events for a long time only the latest are kept.
+const maxMetaComments = 64
+
+// EventHandler processes one SSE event dispatched by StreamEvents.
+// Returning a non-nil error stops the stream; StreamEvents returns it
+// unchanged.
+type EventHandler func(ctx context.Context, ev *SSEEvent, meta SSEEventMeta) error
+
+// JSONEventHandler adapts a typed handler to an EventHandler: the event
+// data is decoded as JSON into a new T before handler is called.
+func JSONEventHandler[T any](handler func(ctx context.Context, v *T) error) EventHandler {
+	return func(ctx context.Context, ev *SSEEvent, _ SSEEventMeta) error {
+		var v T
+		if err := json.Unmarshal(ev.Data, &v); err != nil {
+			return fmt.Errorf("decode %q event: %w", ev.Event, err)
+		}
+		return handler(ctx, &v)
+	}
+}
+
+// StreamEvents opens an SSE connection to GET path?query and routes each
+// event to the handler registered under its name in handlers, so a single
+// connection can carry several event types. Events without an "event:"
+// field are dispatched under "message", the SSE default name.
+//
+// Event filtering and termination follow the same rules as StreamFacts:
+// keepalive comments and events without a registered handler are
+// skipped, an event with empty data is an error, and the call returns nil
+// when the server closes the stream.
+func (c *Client) StreamEvents(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	handlers map[string]EventHandler,
+) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("StreamEvents: path must not be empty")
+	}
+	if len(handlers) == 0 {
+		return errors.New("StreamEvents: handlers must not be empty")
+	}
+	return c.runStream(ctx, sseStream{
+		op:           "StreamEvents",
+		endpoint:     path,
+		query:        query,
+		handlers:     handlers,
+		defaultEvent: "message",
+		opt:          StreamOptions{keepComments: true},
+	})
+}
+
+// sseStream describes a single SSE subscription executed by runStream.
+type sseStream struct {
+	// op is the public method name used as error message prefix.
+	op string
+
+	// endpoint is the relative API path, e.g. "/api/facts/items/stream".
+	endpoint string
+
+	// query holds the already validated query parameters.
+	query url.Values
+
+	// handlers maps SSE event names to the handlers they are dispatched
+	// to; events with a name not present in the map are ignored.
+	handlers map[string]EventHandler
+
+	// defaultEvent is the name used to dispatch events without an
+	// "event:" field.
+	defaultEvent string
+
+	// opt carries the caller-provided stream behaviour.
+	opt StreamOptions
+
+	// lastEventID, if set, is sent as the Last-Event-ID header when
+	// reconnecting.
+	lastEventID string
+
+	// onRetry, if set, receives the raw "retry:" field of every event
+	// carrying one, including events without data.
+	onRetry func(retry string)
+}
+
+// runStream opens the SSE connection described by s and dispatches every
+// event that passes the common filtering rules to s.handlers:
+//   - pure comment events (keepalives, start/end markers) are skipped;
+//   - events without a handler for their name are skipped;
+//   - events without data are reported as an error;
+//   - duplicates are skipped when s.opt.DedupByID is set.
+//
+// Handlers are responsible for decoding the payload; a non-nil error from
+// one terminates the stream and is returned unchanged. A clean EOF from
+// the server yields a nil error.
+//
+// When s.opt.keepComments is set, the comment-only events observed since
+// the previously delivered event are collected, up to maxMetaComments,
+// and passed to the handler as part of SSEEventMeta.
+func (c *Client) runStream(ctx context.Context, s sseStream) error {
+	// reqCtx carries the operation tag and lets the idle timer abort a
+	// stalled connection without cancelling the caller's context.
+	reqCtx, cancelReq := context.WithCancel(withOperation(ctx, s.op))
+	defer cancelReq()
+
+	var idle *idleTimer
+	if s.opt.IdleTimeout > 0 {
+		idle = newIdleTimer(s.opt.IdleTimeout, cancelReq)
+		defer idle.stop()
+	}
+
+	req, err := c.newRequest(reqCtx, http.MethodGet, s.endpoint, s.query, nil)
+	if err != nil {
+		return fmt.Errorf("%s: create request: %w", s.op, err)
+	}
+
+	// SSE best practice: explicitly express preference for text/event-stream.
+	h := http.Header{}
+	h.Set("Accept", "text/event-stream")
+	if s.lastEventID != "" {
+		h.Set("Last-Event-ID", s.lastEventID)
+	}
+	c.applyHeaders(req, h)
+
+	start := time.Now()
+	resp, err := c.send(req)
+	if err != nil {
+		// If context has been cancelled, surface context error directly.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if idle.fired() {
+			return fmt.Errorf("%s: %w", s.op, ErrStreamIdle)
+		}
+		return fmt.Errorf("%s: %w", s.op, err)
+	}
+	defer resp.Body.Close()
+	c.logWarnings(req.Context(), resp)
+	c.recordRateLimit(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Read limited body to avoid unbounded memory usage.
+		return c.readAPIError(resp, start)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); !c.lenientStreamContentType && !isEventStream(ct) {
+		return fmt.Errorf("%s: %w: %q", s.op, ErrUnexpectedContentType, ct)
+	}
+
+	if s.opt.RejectNonStreaming && isNonStreamingResponse(resp) {
+		return fmt.Errorf("%s: %w (Content-Length=%d, proto=%s)", s.op, ErrNotStreaming, resp.ContentLength, resp.Proto)
+	}
+
+	if s.opt.onConnected != nil {
+		s.opt.onConnected()
+	}
+
+	var body io.Reader = resp.Body
+	if idle != nil {
+		body = &idleResetReader{r: body, idle: idle}
+	}
+	if s.opt.MaxStreamBytes > 0 {
+		body = &maxBytesReader{r: body, remaining: s.opt.MaxStreamBytes}
+	}
+
+	if s.opt.RawSink != nil {
+		body = &rawSinkReader{
+			r:     body,
+			sink:  s.opt.RawSink,
+			fatal: s.opt.RawSinkErrorFatal,
+			onDetach: func(err error) {
+				if c.logger != nil {
+					c.logger.Printf("manaxclient: %s: raw sink detached after write error: %v", s.op, err)
+				}
+			},
+		}
+	}
+
+	reader := newSSEReader(body)
+	lastID := ""
+	delivered := 0
+	ready := false
+	markReady := func() {
+		if !ready {
+			ready = true
+			if s.opt.OnStreamReady != nil {
+				s.opt.OnStreamReady()
+			}
+		}
+	}
+	var comments []string
+
+	for {
+		ev, err := reader.ReadEvent()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// Normal termination: server closed the stream.
+				// If the caller wants automatic reconnection, they
+				// can implement it around the streaming methods.
+				return nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				// Prefer propagating context cancellation error when
+				// both a read error and a cancelled context exist.
+				return ctxErr
+			}
+			if idle.fired() {
+				return fmt.Errorf("%s: %w", s.op, ErrStreamIdle)
+			}
+			return fmt.Errorf("%s: read SSE event: %w", s.op, err)
+		}
+		if ev == nil {
+			continue
+		}
+
+		// Ignore pure comment events (keepalives, stream markers).
+		if ev.IsComment() {
+			if s.opt.keepComments {
+				if len(comments) == maxMetaComments {
+					comments = append(comments[:0], comments[1:]...)
+				}
+				comments = append(comments, ev.Comment)
+			}
+			if s.opt.OnComment != nil {
+				s.opt.OnComment(ev.Comment)
+			}
+			if isStreamStartMarker(ev.Comment) {
+				markReady()
+			}
+			continue
+		}
+
+		if ev.Retry != "" && s.onRetry != nil {
+			s.onRetry(ev.Retry)
+		}
+		// An unnamed event without data only updates the connection
+		// state (id, retry) and is not dispatched.
+		if len(ev.Data) == 0 && ev.Event == "" && (ev.ID != "" || ev.Retry != "") {
+			continue
+		}
+
+		// Only process event types with a registered handler; ignore any
+		// other event types to keep the stream forwards-compatible.
+		name := ev.Event
+		if name == "" {
+			name = s.defaultEvent
+		}
+		handler, ok := s.handlers[name]
+		if !ok || handler == nil {
+			continue
+		}
+
+		if len(ev.Data) == 0 {
+			// Malformed event: event type without data.
+			// Treat as error to avoid silently hiding server bugs.
+			return fmt.Errorf("%s: received event %q with empty data payload", s.op, name)
+		}
+
+		if s.opt.DedupByID && isDuplicateEventID(ev.ID, lastID) {
+			continue
+		}
+
+		markReady()
+
+		meta := SSEEventMeta{
+			ID:       ev.ID,
+			Retry:    ev.Retry,
+			Comments: comments,
+		}
+		comments = nil
+
+		start := time.Now()
+		if err := s.callHandler(ctx, handler, ev, meta); err != nil {
+			return err
+		}
+		if err := c.checkHandlerDuration(s, name, time.Since(start)); err != nil {
+			return err
+		}
+
+		if ev.ID != "" {
+			lastID = ev.ID
+		}
+
+		delivered++
+		if s.opt.MaxEvents > 0 && delivered >= s.opt.MaxEvents {
+			return nil
+		}
+	}
+}
+
+// streamReconnect carries the state shared between the handlers of a
+// reconnecting stream and runStreamReconnecting.
+type streamReconnect struct {
+	// stopErr records errors that must end the stream even when
+	// reconnecting: decode failures and handler errors. Handlers set it.
+	stopErr error
+
+	// handled counts the events handled on the current connection.
+	// Handlers increment it; it is reset before every connection.
+	handled int
+
+	// retry is the last "retry:" value received from the server.
+	retry string
+
+	// prepare, if set, is called before every connection to refresh the
+	// resume state of s (query, lastEventID).
+	prepare func(s *sseStream)
+
+	// onReconnect, if set, is called before every reconnection.
+	onReconnect func(RetryEvent)
+}
+
+// runStreamReconnecting runs s and reopens it after the server closes it
+// or a transient error occurs, until ctx is cancelled, a handler fails
+// (r.stopErr), s.opt.MaxEvents is reached or policy gives up. The delay
+// before a reconnection is the server's last "retry:" value if any, the
+// policy's backoff otherwise; each reconnection consumes one unit of the
+// ctx retry budget, if any.
+func (c *Client) runStreamReconnecting(ctx context.Context, s sseStream, policy ReconnectPolicy, r *streamReconnect) error {
+	s.onRetry = func(v string) { r.retry = v }
+	backoff := newReconnectBackoff(policy)
+	for {
+		r.handled = 0
+		if r.prepare != nil {
+			r.prepare(&s)
+		}
+		err := c.runStream(ctx, s)
+		if r.stopErr != nil {
+			return r.stopErr
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if s.opt.MaxEvents > 0 && r.handled >= s.opt.MaxEvents {
+			return nil
+		}
+		if err != nil && (isPermanentStreamError(err) || errors.Is(err, ErrHandlerPanic) || errors.Is(err, ErrHandlerTooSlow) ||
+			errors.Is(err, ErrNotStreaming) || errors.Is(err, ErrStreamTooLarge)) {
+			return err
+		}
+		if r.handled > 0 {
+			backoff.reset()
+		}
+
+		delay, ok := backoff.next()
+		if !ok {
+			if err == nil {
+				err = errors.New("server closed the stream")
+			}
+			return fmt.Errorf("%s: giving up after %d reconnect attempts: %w", s.op, policy.MaxAttempts, err)
+		}
+		if ms, convErr := strconv.Atoi(r.retry); convErr == nil && ms >= 0 {
+			delay = time.Duration(ms) * time.Millisecond
+		}
+		if budgetErr := consumeRetry(ctx); budgetErr != nil {
+			return fmt.Errorf("%s: reconnect: %w", s.op, budgetErr)
+		}
+		ev := newRetryEvent(s.endpoint, backoff.attempts, err, delay)
+		c.reportRetry(ev)
+		if r.onReconnect != nil {
+			r.onReconnect(ev)
+		}
+
+		if err := waitOrCancel(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// callHandler invokes handler, converting a panic into an error when
+// s.opt.RecoverHandlerPanics is set.
+func (s sseStream) callHandler(ctx context.Context, handler EventHandler, ev *SSEEvent, meta SSEEventMeta) (err error) {
+	if s.opt.RecoverHandlerPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				if rErr, ok := r.(error); ok {
+					err = fmt.Errorf("%s: %w: %w", s.op, ErrHandlerPanic, rErr)
+				} else {
+					err = fmt.Errorf("%s: %w: %v", s.op, ErrHandlerPanic, r)
+				}
+			}
+		}()
+	}
+	return handler(ctx, ev, meta)
+}
+
+// maxBytesReader returns ErrStreamTooLarge once more than the configured
+// number of bytes would be read from r.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// Read implements io.Reader. A stream of exactly the limit size ends
+// normally; only bytes beyond the limit trigger ErrStreamTooLarge.
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		var probe [1]byte
+		n, err := m.r.Read(probe[:])
+		if n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, ErrStreamTooLarge
+	}
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// rawSinkReader copies everything read from r to sink. A sink failure
+// either fails the read (fatal) or detaches the sink, so that the bytes
+// handed to the SSE parser are never affected.
+type rawSinkReader struct {
+	r        io.Reader
+	sink     io.Writer
+	fatal    bool
+	onDetach func(err error)
+}
+
+func (t *rawSinkReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 && t.sink != nil {
+		if _, werr := t.sink.Write(p[:n]); werr != nil {
+			if t.fatal {
+				return 0, fmt.Errorf("raw sink: %w", werr)
+			}
+			t.sink = nil
+			t.onDetach(werr)
+		}
+	}
+	return n, err
+}
+
+// idleTimer calls onIdle when it is not reset within d.
+type idleTimer struct {
+	t      *time.Timer
+	d      time.Duration
+	isIdle atomic.Bool
+}
+
+// newIdleTimer starts an idleTimer that invokes onIdle after d of
+// inactivity.
+func newIdleTimer(d time.Duration, onIdle func()) *idleTimer {
+	it := &idleTimer{d: d}
+	it.t = time.AfterFunc(d, func() {
+		it.isIdle.Store(true)
+		onIdle()
+	})
+	return it
+}
+
+// reset restarts the inactivity period.
+func (it *idleTimer) reset() { it.t.Reset(it.d) }
+
+// stop releases the timer.
+func (it *idleTimer) stop() { it.t.Stop() }
+
+// fired reports whether the timer expired; a nil timer never fires.
+func (it *idleTimer) fired() bool { return it != nil && it.isIdle.Load() }
+
+// idleResetReader resets idle whenever bytes are read from r.
+type idleResetReader struct {
+	r    io.Reader
+	idle *idleTimer
+}
+
+func (ir *idleResetReader) Read(p []byte) (int, error) {
+	n, err := ir.r.Read(p)
+	if n > 0 && !ir.idle.fired() {
+		ir.idle.reset()
+	}
+	return n, err
+}
+
+// isStreamStartMarker reports whether comment is a server start marker
+// such as "matches-stream-start".
+func isStreamStartMarker(comment string) bool {
+	return strings.HasSuffix(comment, "-stream-start")
+}
+
+// isEventStream reports whether the Content-Type value ct is
+// text/event-stream, with or without parameters such as charset.
+func isEventStream(ct string) bool {
+	mt, _, err := mime.ParseMediaType(ct)
+	return err == nil && mt == "text/event-stream"
+}
+
+// isNonStreamingResponse reports whether resp carries a fully-buffered body
+// rather than an open-ended stream: either the length is known upfront,
+// or the connection is close-delimited without chunked transfer encoding
+// (typical for HTTP/1.0 servers).
+func isNonStreamingResponse(resp *http.Response) bool {
+	if resp.ContentLength >= 0 {
+		return true
+	}
+	if resp.ProtoMajor >= 2 {
+		return false
+	}
+	for _, te := range resp.TransferEncoding {
+		if strings.EqualFold(te, "chunked") {
+			return false
+		}
+	}
+	return resp.Close
+}
+
+// isDuplicateEventID reports whether an event with the given id must be
+// skipped because an event with id last (or a later one) was already
+// handled. Empty ids never count as duplicates.
+func isDuplicateEventID(id, last string) bool {
+	if id == "" || last == "" {
+		return false
+	}
+
+	n, errN := strconv.ParseInt(id, 10, 64)
+	l, errL := strconv.ParseInt(last, 10, 64)
+	if errN == nil && errL == nil {
+		return n <= l
+	}
+	return id == last
+}
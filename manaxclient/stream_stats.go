@@ -0,0 +1,109 @@
+package manaxclient
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// streamStatsSamples is the number of most recent handler durations kept
+// by StreamStats for percentile computation.
+const streamStatsSamples = 1024
+
+// StreamStats accumulates handler timings of a stream when set as
+// StreamOptions.Stats. One StreamStats may be shared by successive or
+// reconnected streams; it is safe for concurrent use.
+type StreamStats struct {
+	mu         sync.Mutex
+	events     int
+	slow       int
+	total      time.Duration
+	max        time.Duration
+	samples    []time.Duration
+	nextSample int
+}
+
+// Events returns the number of events whose handler completed.
+func (st *StreamStats) Events() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.events
+}
+
+// SlowEvents returns the number of handler calls that exceeded
+// StreamOptions.HandlerWarnAfter.
+func (st *StreamStats) SlowEvents() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.slow
+}
+
+// HandlerMean returns the mean handler duration, or 0 without events.
+func (st *StreamStats) HandlerMean() time.Duration {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.events == 0 {
+		return 0
+	}
+	return st.total / time.Duration(st.events)
+}
+
+// HandlerMax returns the longest handler duration observed.
+func (st *StreamStats) HandlerMax() time.Duration {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.max
+}
+
+// HandlerP99 returns the 99th percentile of the handler durations of the
+// last 1024 events, or 0 without events.
+func (st *StreamStats) HandlerP99() time.Duration {
+	st.mu.Lock()
+	sorted := slices.Clone(st.samples)
+	st.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+	slices.Sort(sorted)
+	return sorted[(len(sorted)*99+99)/100-1]
+}
+
+// observe records one handler duration.
+func (st *StreamStats) observe(d time.Duration, slow bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.events++
+	st.total += d
+	if d > st.max {
+		st.max = d
+	}
+	if slow {
+		st.slow++
+	}
+	if len(st.samples) < streamStatsSamples {
+		st.samples = append(st.samples, d)
+		return
+	}
+	st.samples[st.nextSample] = d
+	st.nextSample = (st.nextSample + 1) % streamStatsSamples
+}
+
+// checkHandlerDuration records d, the duration of the handler call for
+// event name, warns when it exceeds HandlerWarnAfter and fails with
+// ErrHandlerTooSlow when it exceeds HandlerMaxDuration.
+func (c *Client) checkHandlerDuration(s sseStream, name string, d time.Duration) error {
+	slow := s.opt.HandlerWarnAfter > 0 && d > s.opt.HandlerWarnAfter
+	if s.opt.Stats != nil {
+		s.opt.Stats.observe(d, slow)
+	}
+	if slow && c.logger != nil {
+		c.logger.Printf("manaxclient: %s: %q handler took %s (warning threshold %s)", s.op, name, d, s.opt.HandlerWarnAfter)
+	}
+	if s.opt.HandlerMaxDuration > 0 && d > s.opt.HandlerMaxDuration {
+		return fmt.Errorf("%s: %w: %q handler took %s (limit %s)", s.op, ErrHandlerTooSlow, name, d, s.opt.HandlerMaxDuration)
+	}
+	return nil
+}
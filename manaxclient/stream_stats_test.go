@@ -0,0 +1,79 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStreamStats_SlowHandler verifies that a slow handler is logged and
+// counted, that the timings are exposed, and that HandlerMaxDuration
+// terminates the stream with ErrHandlerTooSlow.
+func TestStreamStats_SlowHandler(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := int64(1); i <= 3; i++ {
+			writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: i})
+		}
+	}
+
+	logger := &recordingLogger{}
+	_, server := newTestClient(t, handler)
+	defer server.Close()
+	client, err := NewClientWithOptions(server.URL, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions failed: %v", err)
+	}
+
+	slowOn2 := func(ctx context.Context, chunk *FactsStreamChunk) error {
+		if chunk.CursorID == 2 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return nil
+	}
+
+	stats := &StreamStats{}
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{Stats: stats, HandlerWarnAfter: 10 * time.Millisecond}}
+	if err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, slowOn2); err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+
+	if stats.Events() != 3 || stats.SlowEvents() != 1 {
+		t.Fatalf("expected 3 events with 1 slow, got %d/%d", stats.Events(), stats.SlowEvents())
+	}
+	if stats.HandlerP99() < 30*time.Millisecond || stats.HandlerMax() != stats.HandlerP99() {
+		t.Fatalf("unexpected timings: p99=%s max=%s", stats.HandlerP99(), stats.HandlerMax())
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "handler took") {
+		t.Fatalf("expected one slow-handler warning, got %q", logger.lines)
+	}
+
+	opt.HandlerMaxDuration = 20 * time.Millisecond
+	err = client.StreamFactsWithOptions(context.Background(), "p_123", opt, slowOn2)
+	if !errors.Is(err, ErrHandlerTooSlow) {
+		t.Fatalf("expected ErrHandlerTooSlow, got %v", err)
+	}
+	if stats.Events() != 5 {
+		t.Fatalf("expected the stream to stop after the slow event, got %d events", stats.Events())
+	}
+}
+
+// TestStreamStats_P99 verifies the nearest-rank percentile over samples.
+func TestStreamStats_P99(t *testing.T) {
+	st := &StreamStats{}
+	if st.HandlerP99() != 0 {
+		t.Fatalf("expected 0 without events")
+	}
+	for i := 1; i <= 200; i++ {
+		st.observe(time.Duration(i)*time.Millisecond, false)
+	}
+	if p := st.HandlerP99(); p != 198*time.Millisecond {
+		t.Fatalf("expected p99 198ms, got %s", p)
+	}
+	if m := st.HandlerMean(); m != 100500*time.Microsecond {
+		t.Fatalf("expected mean 100.5ms, got %s", m)
+	}
+}
@@ -0,0 +1,553 @@
+package manaxclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSSEEventWithID is like writeSSEEvent but also emits an "id:" field.
+func writeSSEEventWithID(t *testing.T, w http.ResponseWriter, event, id string, v any) {
+	t.Helper()
+
+	if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+		t.Fatalf("write SSE id failed: %v", err)
+	}
+	writeSSEEvent(t, w, event, v)
+}
+
+// TestStreamMatches_DedupByID verifies that a retransmitted event (same id
+// as the last handled one) and an older id are skipped when DedupByID is set.
+func TestStreamMatches_DedupByID(t *testing.T) {
+	now := time.Now().UTC()
+
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEventWithID(t, w, "matches", "1", MatchesStreamChunk{CursorID: 1})
+		writeSSEEventWithID(t, w, "matches", "2", MatchesStreamChunk{CursorID: 2})
+		writeSSEEventWithID(t, w, "matches", "2", MatchesStreamChunk{CursorID: 2})
+		writeSSEEventWithID(t, w, "matches", "1", MatchesStreamChunk{CursorID: 1})
+		writeSSEEventWithID(t, w, "matches", "3", MatchesStreamChunk{CursorID: 3})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var got []int64
+	opt := MatchesStreamOptions{
+		Direction:     MatchingDirectionOffer,
+		StreamOptions: StreamOptions{DedupByID: true},
+	}
+	cursor := MatchesStreamCursor{UpdatedUTC: now}
+
+	err := client.StreamMatches(context.Background(), "p_123", cursor, opt, func(ctx context.Context, chunk *MatchesStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMatches returned error: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2 3]" {
+		t.Fatalf("expected chunks [1 2 3], got %v", got)
+	}
+}
+
+// TestStreamFacts_DedupDisabled ensures repeated ids are delivered as-is
+// when deduplication is not requested.
+func TestStreamFacts_DedupDisabled(t *testing.T) {
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEventWithID(t, w, "facts", "a", FactsStreamChunk{CursorID: 1})
+		writeSSEEventWithID(t, w, "facts", "a", FactsStreamChunk{CursorID: 1})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	count := 0
+	err := client.StreamFacts(context.Background(), "p_123", func(ctx context.Context, chunk *FactsStreamChunk) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFacts returned error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 chunks without dedup, got %d", count)
+	}
+}
+
+// TestStreamFacts_DedupNonNumericID verifies that non-numeric ids are only
+// skipped on exact repeats.
+func TestStreamFacts_DedupNonNumericID(t *testing.T) {
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEventWithID(t, w, "facts", "b", FactsStreamChunk{CursorID: 1})
+		writeSSEEventWithID(t, w, "facts", "b", FactsStreamChunk{CursorID: 1})
+		writeSSEEventWithID(t, w, "facts", "a", FactsStreamChunk{CursorID: 2})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var got []int64
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{DedupByID: true}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2]" {
+		t.Fatalf("expected chunks [1 2], got %v", got)
+	}
+}
+
+// bufferedSSEBody is an SSE-shaped payload served with Content-Length,
+// as a misconfigured (non-streaming) server would do.
+const bufferedSSEBody = ": ping\n\n" +
+	"event: facts\ndata: {\"cursorId\":1}\n\n" +
+	"event: facts\ndata: {\"cursorId\":2}\n\n"
+
+// serveBufferedSSE writes bufferedSSEBody with an explicit Content-Length.
+func serveBufferedSSE(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(bufferedSSEBody)))
+	w.Write([]byte(bufferedSSEBody))
+}
+
+// TestStreamFacts_BufferedResponse verifies that a Content-Length SSE
+// response is processed completely and terminates cleanly by default.
+func TestStreamFacts_BufferedResponse(t *testing.T) {
+	client, server := newTestClient(t, serveBufferedSSE)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []int64
+	err := client.StreamFacts(ctx, "p_123", func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFacts returned error: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2]" {
+		t.Fatalf("expected chunks [1 2], got %v", got)
+	}
+}
+
+// TestStreamFacts_RejectNonStreaming verifies that a buffered response is
+// rejected with ErrNotStreaming when RejectNonStreaming is set.
+func TestStreamFacts_RejectNonStreaming(t *testing.T) {
+	client, server := newTestClient(t, serveBufferedSSE)
+	defer server.Close()
+
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{RejectNonStreaming: true}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		t.Fatalf("handler must not be called for a rejected stream")
+		return nil
+	})
+	if !errors.Is(err, ErrNotStreaming) {
+		t.Fatalf("expected ErrNotStreaming, got %v", err)
+	}
+}
+
+// TestStream_UnexpectedContentType verifies that a 200 JSON answer to a
+// stream request fails with ErrUnexpectedContentType, without reconnecting,
+// that parameters such as charset are accepted, and that
+// WithLenientStreamContentType disables the check.
+func TestStream_UnexpectedContentType(t *testing.T) {
+	calls := 0
+	contentType := "application/json"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", contentType)
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{ProID: "p_123", CursorID: 1})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	handled := 0
+	count := func(ctx context.Context, chunk *FactsStreamChunk) error {
+		handled++
+		return nil
+	}
+	opt := FactsStreamOptions{Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, count)
+	if !errors.Is(err, ErrUnexpectedContentType) || !strings.Contains(err.Error(), "application/json") {
+		t.Fatalf("expected ErrUnexpectedContentType, got %v", err)
+	}
+	if calls != 1 || handled != 0 {
+		t.Fatalf("expected a single rejected connection, got calls=%d handled=%d", calls, handled)
+	}
+
+	contentType = "text/event-stream; charset=utf-8"
+	if err := client.StreamFacts(context.Background(), "p_123", count); err != nil || handled != 1 {
+		t.Fatalf("expected charset to be accepted, got err=%v handled=%d", err, handled)
+	}
+
+	contentType = "text/plain"
+	client = newClientWithOptions(t, server.URL, WithLenientStreamContentType())
+	if err := client.StreamFacts(context.Background(), "p_123", count); err != nil || handled != 2 {
+		t.Fatalf("expected a lenient client to parse the stream, got err=%v handled=%d", err, handled)
+	}
+}
+
+// TestStreamFacts_RejectNonStreamingAcceptsChunked ensures a genuinely
+// streamed (chunked) response passes the RejectNonStreaming check.
+func TestStreamFacts_RejectNonStreamingAcceptsChunked(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": ping\n\n"))
+		w.(http.Flusher).Flush()
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 1})
+	})
+	defer server.Close()
+
+	count := 0
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{RejectNonStreaming: true}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 chunk, got %d", count)
+	}
+}
+
+// TestStreamMatches_MaxEvents verifies that exactly MaxEvents chunks are
+// delivered, the stream ends with a nil error, and the handler-maintained
+// cursor reflects the last delivered chunk.
+func TestStreamMatches_MaxEvents(t *testing.T) {
+	now := time.Now().UTC()
+
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 5; i++ {
+			w.Write([]byte(": idle\n\n"))
+			writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorUpdatedUTC: now, CursorID: int64(i)})
+		}
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	cursor := MatchesStreamCursor{UpdatedUTC: now}
+	opt := MatchesStreamOptions{
+		Direction:     MatchingDirectionOffer,
+		StreamOptions: StreamOptions{MaxEvents: 3},
+	}
+
+	count := 0
+	err := client.StreamMatches(context.Background(), "p_123", cursor, opt, func(ctx context.Context, chunk *MatchesStreamChunk) error {
+		count++
+		cursor.ID = chunk.CursorID
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMatches returned error: %v", err)
+	}
+	if count != 3 || cursor.ID != 3 {
+		t.Fatalf("expected 3 chunks and cursor 3, got count=%d cursor=%d", count, cursor.ID)
+	}
+}
+
+// TestStreamMatches_OnStreamReady verifies that OnStreamReady fires once,
+// after the start marker comment and before the first chunk is handled.
+func TestStreamMatches_OnStreamReady(t *testing.T) {
+	now := time.Now().UTC()
+
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": ping\n\n"))
+		w.Write([]byte(": matches-stream-start\n\n"))
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorID: 1})
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorID: 2})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var order []string
+	opt := MatchesStreamOptions{
+		Direction: MatchingDirectionOffer,
+		StreamOptions: StreamOptions{
+			OnStreamReady: func() { order = append(order, "ready") },
+		},
+	}
+	err := client.StreamMatches(context.Background(), "p_123", MatchesStreamCursor{UpdatedUTC: now}, opt,
+		func(ctx context.Context, chunk *MatchesStreamChunk) error {
+			order = append(order, fmt.Sprintf("chunk%d", chunk.CursorID))
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("StreamMatches returned error: %v", err)
+	}
+	if fmt.Sprint(order) != "[ready chunk1 chunk2]" {
+		t.Fatalf("unexpected callback order: %v", order)
+	}
+}
+
+// TestStreamFacts_OnStreamReadyFirstEvent verifies that, without a start
+// marker, OnStreamReady fires right before the first event is handled.
+func TestStreamFacts_OnStreamReadyFirstEvent(t *testing.T) {
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": ping\n\n"))
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 1})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	readyCalls := 0
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{OnStreamReady: func() { readyCalls++ }}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		if readyCalls != 1 {
+			t.Fatalf("expected OnStreamReady before the first chunk, calls=%d", readyCalls)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if readyCalls != 1 {
+		t.Fatalf("expected exactly one OnStreamReady call, got %d", readyCalls)
+	}
+}
+
+// TestStreamOptions_OnComment verifies that OnComment receives every
+// comment, including markers and bare keepalives, in order with the
+// events, and that events are processed as usual.
+func TestStreamOptions_OnComment(t *testing.T) {
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": facts-stream-start\n\n"))
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 1})
+		w.Write([]byte(": ping\n\n:\n\n"))
+		w.Write([]byte(": facts-stream-end\n\n"))
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var got []string
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{
+		OnComment: func(comment string) { got = append(got, "comment:"+comment) },
+	}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, fmt.Sprintf("chunk%d", chunk.CursorID))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	want := "[comment:facts-stream-start chunk1 comment:ping comment: comment:facts-stream-end]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("expected %s, got %v", want, got)
+	}
+}
+
+// TestStreamFacts_MaxStreamBytesCompressed verifies that a small gzip
+// stream inflating beyond MaxStreamBytes is terminated with
+// ErrStreamTooLarge, after delivering the events within the limit.
+func TestStreamFacts_MaxStreamBytesCompressed(t *testing.T) {
+	handlerHTTP := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		writeSSEEvent(t, gz, "facts", FactsStreamChunk{CursorID: 1})
+		// ~1 MiB of keepalives compresses to a few KiB.
+		gz.Write(bytes.Repeat([]byte(": ping\n\n"), 128*1024))
+		writeSSEEvent(t, gz, "facts", FactsStreamChunk{CursorID: 2})
+	}
+
+	client, server := newTestClient(t, handlerHTTP)
+	defer server.Close()
+
+	var got []int64
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{MaxStreamBytes: 64 * 1024}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		got = append(got, chunk.CursorID)
+		return nil
+	})
+	if !errors.Is(err, ErrStreamTooLarge) {
+		t.Fatalf("expected ErrStreamTooLarge, got %v", err)
+	}
+	if fmt.Sprint(got) != "[1]" {
+		t.Fatalf("expected only the first chunk, got %v", got)
+	}
+}
+
+// TestStreamFacts_MaxStreamBytesExactLimit ensures a stream whose size is
+// exactly the limit terminates normally.
+func TestStreamFacts_MaxStreamBytesExactLimit(t *testing.T) {
+	client, server := newTestClient(t, serveBufferedSSE)
+	defer server.Close()
+
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{MaxStreamBytes: int64(len(bufferedSSEBody))}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected clean termination, got %v", err)
+	}
+}
+
+// TestStreamEvents_Dispatch verifies that a single stream carrying two
+// event types routes each event to its typed handler, and that unnamed
+// and unknown events are handled per the documented rules.
+func TestStreamEvents_Dispatch(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/events" || r.URL.Query().Get("proId") != "p_123" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 1})
+		writeSSEEvent(t, w, "matches", MatchesStreamChunk{CursorID: 2})
+		writeSSEEvent(t, w, "unknown", map[string]int{"x": 1})
+		_, _ = w.Write([]byte("data: hello\n\n"))
+		// A bare ":" keepalive must not reach the default handler.
+		_, _ = w.Write([]byte(":\n\n"))
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 3})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var got []string
+	handlers := map[string]EventHandler{
+		"facts": JSONEventHandler(func(ctx context.Context, c *FactsStreamChunk) error {
+			got = append(got, fmt.Sprintf("facts:%d", c.CursorID))
+			return nil
+		}),
+		"matches": JSONEventHandler(func(ctx context.Context, c *MatchesStreamChunk) error {
+			got = append(got, fmt.Sprintf("matches:%d", c.CursorID))
+			return nil
+		}),
+		"message": func(ctx context.Context, ev *SSEEvent, meta SSEEventMeta) error {
+			got = append(got, "message:"+string(ev.Data))
+			return nil
+		},
+	}
+	err := client.StreamEvents(context.Background(), "/api/events", url.Values{"proId": {"p_123"}}, handlers)
+	if err != nil {
+		t.Fatalf("StreamEvents returned error: %v", err)
+	}
+
+	want := "facts:1 matches:2 message:hello facts:3"
+	if s := strings.Join(got, " "); s != want {
+		t.Fatalf("expected dispatch %q, got %q", want, s)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+// TestStreamOptions_RawSink verifies that the raw sink receives the exact
+// bytes sent by the server, and that sink write errors either detach the
+// sink or terminate the stream depending on RawSinkErrorFatal.
+func TestStreamOptions_RawSink(t *testing.T) {
+	raw := ": facts-stream-start\n\n" +
+		"event: facts\nid: 1\ndata: {\"cursorId\":1}\n\n" +
+		": ping\n\n" +
+		"event: facts\ndata: {\"cursorId\":2,\n" +
+		"data: \"items\":[]}\n\n"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(raw))
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	var ids []int64
+	collect := func(ctx context.Context, chunk *FactsStreamChunk) error {
+		ids = append(ids, chunk.CursorID)
+		return nil
+	}
+
+	var sink bytes.Buffer
+	err := client.StreamFactsWithOptions(context.Background(), "p_123",
+		FactsStreamOptions{StreamOptions: StreamOptions{RawSink: &sink}}, collect)
+	if err != nil {
+		t.Fatalf("StreamFactsWithOptions returned error: %v", err)
+	}
+	if sink.String() != raw {
+		t.Fatalf("raw sink mismatch:\n got %q\nwant %q", sink.String(), raw)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("unexpected decoded chunks: %v", ids)
+	}
+
+	ids = nil
+	err = client.StreamFactsWithOptions(context.Background(), "p_123",
+		FactsStreamOptions{StreamOptions: StreamOptions{RawSink: failingWriter{}}}, collect)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("expected decoding to continue after a non-fatal sink error, got err=%v ids=%v", err, ids)
+	}
+
+	ids = nil
+	err = client.StreamFactsWithOptions(context.Background(), "p_123",
+		FactsStreamOptions{StreamOptions: StreamOptions{RawSink: failingWriter{}, RawSinkErrorFatal: true}}, collect)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected fatal sink error, got %v", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no chunks after fatal sink error, got %v", ids)
+	}
+}
+
+// TestStreamOptions_RecoverHandlerPanics verifies that a panicking handler
+// terminates the stream with ErrHandlerPanic in recover mode.
+func TestStreamOptions_RecoverHandlerPanics(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 1})
+		writeSSEEvent(t, w, "facts", FactsStreamChunk{CursorID: 2})
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	calls := 0
+	opt := FactsStreamOptions{StreamOptions: StreamOptions{RecoverHandlerPanics: true}}
+	err := client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		calls++
+		panic("boom")
+	})
+	if !errors.Is(err, ErrHandlerPanic) || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected ErrHandlerPanic with panic value, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected stream to stop after the panic, got %d calls", calls)
+	}
+
+	cause := errors.New("bad state")
+	err = client.StreamFactsWithOptions(context.Background(), "p_123", opt, func(ctx context.Context, chunk *FactsStreamChunk) error {
+		panic(cause)
+	})
+	if !errors.Is(err, ErrHandlerPanic) || !errors.Is(err, cause) {
+		t.Fatalf("expected panic error to be wrapped, got %v", err)
+	}
+}
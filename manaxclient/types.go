@@ -2,6 +2,8 @@ package manaxclient
 
 import (
 	"encoding/json"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -73,6 +75,13 @@ type UploadSpeechAudioRequest struct {
 	// SampleRate is the sampling rate in Hz; if 0, it is omitted and the
 	// server may auto-detect or use a default.
 	SampleRate int
+
+	// Final marks the last chunk of the session and is sent as the form
+	// field final=true. Servers that support it treat the session as
+	// complete and trigger the final ASR pass; the chunk may be empty,
+	// acting as a pure end-of-session marker. Servers that do not know
+	// the field ignore it.
+	Final bool
 }
 
 // SpeechUploadResponse mirrors the C# SpeechUploadResponse model in
@@ -114,6 +123,23 @@ type SpeechUploadResponse struct {
 	Transcript string `json:"transcript"`
 }
 
+// NormalizedWavPath returns Wav16kMonoPath and true when the server
+// reported a normalized WAV file. A missing, null or empty path yields
+// ("", false).
+func (r *SpeechUploadResponse) NormalizedWavPath() (string, bool) {
+	if r == nil || r.Wav16kMonoPath == nil || *r.Wav16kMonoPath == "" {
+		return "", false
+	}
+	return *r.Wav16kMonoPath, true
+}
+
+// HasNormalizedWav reports whether the normalized 16kHz mono WAV file is
+// available (see NormalizedWavPath).
+func (r *SpeechUploadResponse) HasNormalizedWav() bool {
+	_, ok := r.NormalizedWavPath()
+	return ok
+}
+
 // UploadSpeechTextRequest represents the JSON payload sent to
 // POST /api/speech/text to attach raw text to a given speech chunk.
 type UploadSpeechTextRequest struct {
@@ -198,6 +224,50 @@ type FactItem struct {
 	IsWritable      bool       `json:"isWritable"`
 }
 
+// FalseReasonDetail is the structured form of FactItem.FalseReason.
+//
+// Newer servers may encode the reason as a JSON object such as
+//   {"code": "contradicted", "message": "superseded by fact #42"}
+// while older ones send free text. Both are represented by this type.
+type FalseReasonDetail struct {
+	// Code is a short machine-friendly reason code; empty for plain-text
+	// reasons.
+	Code string `json:"code"`
+
+	// Message is the human-readable explanation. For plain-text reasons
+	// it holds the whole text.
+	Message string `json:"message"`
+
+	// Raw is the original, unparsed FalseReason string.
+	Raw string `json:"-"`
+}
+
+// FalseReasonDetail parses FalseReason into a FalseReasonDetail.
+//
+// It returns nil when FalseReason is nil or blank. A reason that is a JSON
+// object is decoded into Code and Message; anything else (plain text or
+// non-object JSON) is returned as Message with an empty Code. Raw always
+// holds the original string.
+func (f FactItem) FalseReasonDetail() *FalseReasonDetail {
+	if f.FalseReason == nil {
+		return nil
+	}
+	raw := *f.FalseReason
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		var d FalseReasonDetail
+		if err := json.Unmarshal([]byte(trimmed), &d); err == nil {
+			d.Raw = raw
+			return &d
+		}
+	}
+	return &FalseReasonDetail{Message: trimmed, Raw: raw}
+}
+
 // FactsItemsResponse represents the response of
 // GET /api/facts/items/snapshot for a specific proId.
 //
@@ -218,6 +288,39 @@ type FactsItemsResponse struct {
 
 	// Items is the list of fact items included in this snapshot.
 	Items []FactItem `json:"items"`
+
+	// Total is the total number of facts matching the request, of which
+	// Items is a window, when the server reports it ("total"); nil for
+	// servers that do not.
+	Total *int `json:"total,omitempty"`
+}
+
+// RemainingPages returns how many more pages of pageSize items are needed
+// to fetch the facts not included in r, based on Total. ok is false when
+// the server did not report Total or pageSize is not positive.
+func (r *FactsItemsResponse) RemainingPages(pageSize int) (pages int, ok bool) {
+	if r.Total == nil || pageSize <= 0 {
+		return 0, false
+	}
+	left := *r.Total - len(r.Items)
+	if left <= 0 {
+		return 0, true
+	}
+	return (left + pageSize - 1) / pageSize, true
+}
+
+// FactsSnapshotRequest describes the input of GetFactsSnapshotWithRequest.
+type FactsSnapshotRequest struct {
+	// ProID is the logical profile id (required).
+	ProID string
+
+	// Limit is the maximum number of items to return; 0 lets the server
+	// choose its default, LimitAll requests its maximum.
+	Limit int
+
+	// Query is an optional free-text search over FactText, sent as q=...
+	// and also applied client-side (see GetFactsSnapshotWithRequest).
+	Query string
 }
 
 // FactsUpdatesResponse represents the response of
@@ -251,6 +354,47 @@ type PatchReviewStatusRequest struct {
 	ReviewStatus string `json:"reviewStatus"`
 }
 
+// Review status values accepted by PatchFactReviewStatus and the other
+// review methods. The empty string clears the status.
+const (
+	ReviewStatusOK  = "ok"
+	ReviewStatusNot = "not"
+)
+
+// encodeReviewStatus returns the wire spelling of a review status:
+// ReviewStatusOK and ReviewStatusNot in lower case whatever the case of
+// status, "" to clear, and any other value trimmed but otherwise
+// unchanged, so that the server can reject it.
+func encodeReviewStatus(status string) string {
+	v := strings.TrimSpace(status)
+	switch {
+	case strings.EqualFold(v, ReviewStatusOK):
+		return ReviewStatusOK
+	case strings.EqualFold(v, ReviewStatusNot):
+		return ReviewStatusNot
+	default:
+		return v
+	}
+}
+
+// PatchFactReviewStatusRequest describes the input of
+// PatchFactReviewStatusWithRequest.
+type PatchFactReviewStatusRequest struct {
+	// ProID is the logical profile id (required).
+	ProID string
+
+	// ID is the fact id (required, > 0).
+	ID int64
+
+	// ReviewStatus is the new status ("ok", "not", or "" to clear).
+	ReviewStatus string
+
+	// ExpectedUpdatedUTC, when non-zero, makes the patch conditional:
+	// the server applies it only if the fact's UpdatedUTC still equals
+	// this value, typically the one read together with the fact.
+	ExpectedUpdatedUTC time.Time
+}
+
 // PatchReviewStatusResponse mirrors the C# PatchReviewStatusResponse
 // type on the ApiService side (code, reason).
 //
@@ -275,6 +419,11 @@ type PatchReviewStatusResponse struct {
 //
 // The HTTP API accepts these values as query parameters, typically in
 // the same PascalCase spelling.
+//
+// Decoded responses keep the direction exactly as sent, including values
+// a newer server may add. Compare with the constants only after checking
+// IsKnown, or through DirectionSet, which classifies case-insensitively
+// and never places an unknown direction in a set.
 type MatchingDirection string
 
 const (
@@ -287,6 +436,120 @@ const (
 	MatchingDirectionSeek MatchingDirection = "Seek"
 )
 
+// Query returns the wire spelling of d used in the direction=... query
+// parameter: the PascalCase name of a known direction, whatever the case
+// of d ("offer" is sent as "Offer"), and any other value trimmed but
+// otherwise unchanged, so that the server can reject it.
+func (d MatchingDirection) Query() string {
+	v := strings.TrimSpace(string(d))
+	switch {
+	case strings.EqualFold(v, string(MatchingDirectionOffer)):
+		return string(MatchingDirectionOffer)
+	case strings.EqualFold(v, string(MatchingDirectionSeek)):
+		return string(MatchingDirectionSeek)
+	default:
+		return v
+	}
+}
+
+// IsKnown reports whether d is one of the directions this package knows
+// (MatchingDirectionOffer or MatchingDirectionSeek), ignoring case and
+// surrounding spaces as Query does.
+func (d MatchingDirection) IsKnown() bool {
+	q := MatchingDirection(d.Query())
+	return q == MatchingDirectionOffer || q == MatchingDirectionSeek
+}
+
+// DirectionSet is a set of matching directions. It lets callers treat a
+// single direction and "both directions" uniformly (see
+// MatchesUpdatesResponse.DirectionOrBoth).
+type DirectionSet uint8
+
+const (
+	// DirectionSetOffer contains MatchingDirectionOffer only.
+	DirectionSetOffer DirectionSet = 1 << iota
+
+	// DirectionSetSeek contains MatchingDirectionSeek only.
+	DirectionSetSeek
+
+	// DirectionSetBoth contains both directions.
+	DirectionSetBoth = DirectionSetOffer | DirectionSetSeek
+)
+
+// Has reports whether d is in the set. Directions are compared as by
+// Query; an unknown direction is never in a set.
+func (s DirectionSet) Has(d MatchingDirection) bool {
+	switch MatchingDirection(d.Query()) {
+	case MatchingDirectionOffer:
+		return s&DirectionSetOffer != 0
+	case MatchingDirectionSeek:
+		return s&DirectionSetSeek != 0
+	default:
+		return false
+	}
+}
+
+// Directions returns the directions in the set, Offer first.
+func (s DirectionSet) Directions() []MatchingDirection {
+	var out []MatchingDirection
+	if s&DirectionSetOffer != 0 {
+		out = append(out, MatchingDirectionOffer)
+	}
+	if s&DirectionSetSeek != 0 {
+		out = append(out, MatchingDirectionSeek)
+	}
+	return out
+}
+
+// MatchesSort selects the server-side ordering of a matches snapshot.
+// The empty value keeps the server's default order.
+type MatchesSort string
+
+const (
+	// MatchesSortScoreAsc orders matches by ascending score.
+	MatchesSortScoreAsc MatchesSort = "score_asc"
+
+	// MatchesSortScoreDesc orders matches by descending score.
+	MatchesSortScoreDesc MatchesSort = "score_desc"
+
+	// MatchesSortUpdatedDesc orders matches by most recently updated first.
+	MatchesSortUpdatedDesc MatchesSort = "updated_desc"
+)
+
+// valid reports whether s is empty or one of the known sort values.
+func (s MatchesSort) valid() bool {
+	switch s {
+	case "", MatchesSortScoreAsc, MatchesSortScoreDesc, MatchesSortUpdatedDesc:
+		return true
+	}
+	return false
+}
+
+// MatchesSnapshotRequest describes the input of
+// GetMatchesSnapshotWithRequest.
+type MatchesSnapshotRequest struct {
+	// ProID is the logical profile id (required).
+	ProID string
+
+	// Direction is "Offer" or "Seek" (required).
+	Direction MatchingDirection
+
+	// MatchesFilter holds the optional score, limit and rationale filters.
+	MatchesFilter
+
+	// Sort requests a server-side order, sent as sort=...; it must be
+	// empty or one of the MatchesSort constants.
+	Sort MatchesSort
+}
+
+// StreamOptions returns the MatchesStreamOptions that follow the matches
+// selected by r: same direction, score, limit and rationale filters, so
+// that a stream opened after the snapshot does not drift from it. Sort has
+// no stream counterpart and is dropped; StreamOptions is left zero.
+func (r MatchesSnapshotRequest) StreamOptions() MatchesStreamOptions {
+	return r.MatchesFilter.streamOptions(r.Direction)
+}
+
 // MatchItem models a single match row as returned by the matching engine.
 //
 // It mirrors MatchItemDto in the FactsEngine, including:
@@ -364,3 +627,22 @@ type MatchesUpdatesResponse struct {
 	// Items contains incremental match items since the last cursor.
 	Items []MatchItem `json:"items"`
 }
+
+// DirectionOrBoth returns the directions covered by the response:
+// DirectionSetBoth when Direction is nil, otherwise the set holding
+// *Direction, compared as by MatchingDirection.Query. An unrecognized
+// direction yields an empty set rather than being mistaken for one of
+// the known ones.
+func (r *MatchesUpdatesResponse) DirectionOrBoth() DirectionSet {
+	if r.Direction == nil {
+		return DirectionSetBoth
+	}
+	switch MatchingDirection(r.Direction.Query()) {
+	case MatchingDirectionOffer:
+		return DirectionSetOffer
+	case MatchingDirectionSeek:
+		return DirectionSetSeek
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,230 @@
+package manaxclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFactItemFalseReasonDetail_JSON verifies that a JSON-encoded reason is
+// decoded into Code and Message while keeping the raw string.
+func TestFactItemFalseReasonDetail_JSON(t *testing.T) {
+	raw := `{"code":"contradicted","message":"superseded by fact #42"}`
+	f := FactItem{Status: "false", FalseReason: &raw}
+
+	d := f.FalseReasonDetail()
+	if d == nil {
+		t.Fatalf("expected detail, got nil")
+	}
+	if d.Code != "contradicted" || d.Message != "superseded by fact #42" || d.Raw != raw {
+		t.Fatalf("unexpected detail: %#v", d)
+	}
+}
+
+// TestFactItemFalseReasonDetail_PlainText verifies that free-text reasons
+// are exposed as Message with an empty Code.
+func TestFactItemFalseReasonDetail_PlainText(t *testing.T) {
+	raw := "  user said otherwise "
+	f := FactItem{Status: "false", FalseReason: &raw}
+
+	d := f.FalseReasonDetail()
+	if d == nil {
+		t.Fatalf("expected detail, got nil")
+	}
+	if d.Code != "" || d.Message != "user said otherwise" || d.Raw != raw {
+		t.Fatalf("unexpected detail: %#v", d)
+	}
+}
+
+// TestFactItemFalseReasonDetail_Absent verifies nil is returned when no
+// reason is present, and malformed JSON falls back to plain text.
+func TestFactItemFalseReasonDetail_Absent(t *testing.T) {
+	if d := (FactItem{}).FalseReasonDetail(); d != nil {
+		t.Fatalf("expected nil for missing reason, got %#v", d)
+	}
+
+	broken := "{not json"
+	d := FactItem{FalseReason: &broken}.FalseReasonDetail()
+	if d == nil || d.Code != "" || d.Message != broken {
+		t.Fatalf("unexpected detail for malformed JSON: %#v", d)
+	}
+}
+
+// TestMatchesUpdatesResponse_DirectionOrBoth verifies the set returned for
+// nil (both), Offer, Seek and unknown directions.
+func TestMatchesUpdatesResponse_DirectionOrBoth(t *testing.T) {
+	offer, seek, other := MatchingDirectionOffer, MatchingDirectionSeek, MatchingDirection("Other")
+	cases := []struct {
+		dir       *MatchingDirection
+		want      DirectionSet
+		hasOffer  bool
+		hasSeek   bool
+		numValues int
+	}{
+		{nil, DirectionSetBoth, true, true, 2},
+		{&offer, DirectionSetOffer, true, false, 1},
+		{&seek, DirectionSetSeek, false, true, 1},
+		{&other, 0, false, false, 0},
+	}
+	for _, tc := range cases {
+		r := &MatchesUpdatesResponse{Direction: tc.dir}
+		got := r.DirectionOrBoth()
+		if got != tc.want {
+			t.Fatalf("direction %v: expected set %d, got %d", tc.dir, tc.want, got)
+		}
+		if got.Has(offer) != tc.hasOffer || got.Has(seek) != tc.hasSeek || got.Has(other) {
+			t.Fatalf("direction %v: unexpected membership for set %d", tc.dir, got)
+		}
+		if n := len(got.Directions()); n != tc.numValues {
+			t.Fatalf("direction %v: expected %d directions, got %d", tc.dir, tc.numValues, n)
+		}
+	}
+}
+
+// TestFactsItemsResponse_Total verifies that "total" is decoded when
+// present, stays nil when absent, and drives RemainingPages.
+func TestFactsItemsResponse_Total(t *testing.T) {
+	var with FactsItemsResponse
+	if err := json.Unmarshal([]byte(`{"proId":"p_123","items":[{"id":1},{"id":2}],"total":7}`), &with); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if with.Total == nil || *with.Total != 7 {
+		t.Fatalf("expected Total 7, got %v", with.Total)
+	}
+	if pages, ok := with.RemainingPages(2); !ok || pages != 3 {
+		t.Fatalf("expected 3 remaining pages, got %d ok=%v", pages, ok)
+	}
+	if pages, ok := with.RemainingPages(5); !ok || pages != 1 {
+		t.Fatalf("expected 1 remaining page, got %d ok=%v", pages, ok)
+	}
+	if _, ok := with.RemainingPages(0); ok {
+		t.Fatalf("expected ok=false for a zero page size")
+	}
+
+	var without FactsItemsResponse
+	if err := json.Unmarshal([]byte(`{"proId":"p_123","items":[{"id":1}]}`), &without); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if without.Total != nil {
+		t.Fatalf("expected nil Total, got %d", *without.Total)
+	}
+	if _, ok := without.RemainingPages(10); ok {
+		t.Fatalf("expected ok=false without Total")
+	}
+	if data, _ := json.Marshal(without); strings.Contains(string(data), "total") {
+		t.Fatalf("expected total to be omitted when nil: %s", data)
+	}
+}
+
+// TestEnumWireEncoding verifies the query spelling of every matching
+// direction and the body spelling of every review status.
+func TestEnumWireEncoding(t *testing.T) {
+	directions := map[MatchingDirection]string{
+		MatchingDirectionOffer: "Offer",
+		MatchingDirectionSeek:  "Seek",
+		"offer":                "Offer",
+		" SEEK ":               "Seek",
+		"Both":                 "Both",
+		"":                     "",
+	}
+	for d, want := range directions {
+		if got := d.Query(); got != want {
+			t.Errorf("MatchingDirection(%q).Query() = %q, want %q", d, got, want)
+		}
+	}
+
+	statuses := map[string]string{
+		ReviewStatusOK:  "ok",
+		ReviewStatusNot: "not",
+		"OK":            "ok",
+		" Not ":         "not",
+		"":              "",
+		"maybe":         "maybe",
+	}
+	for s, want := range statuses {
+		if got := encodeReviewStatus(s); got != want {
+			t.Errorf("encodeReviewStatus(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+// TestMatchingDirection_Unknown verifies that an unknown direction is
+// decoded unchanged, reported by IsKnown and kept out of direction sets,
+// while a known one in another case is still classified.
+func TestMatchingDirection_Unknown(t *testing.T) {
+	var upd MatchesUpdatesResponse
+	data := `{"proId":"p_123","direction":"Mutual","items":[{"id":1,"direction":"Mutual"},{"id":2,"direction":"seek"}]}`
+	if err := json.Unmarshal([]byte(data), &upd); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if upd.Direction == nil || *upd.Direction != "Mutual" || upd.Items[0].Direction != "Mutual" {
+		t.Fatalf("expected the unknown direction to be preserved, got %+v", upd)
+	}
+	if upd.Direction.IsKnown() || upd.Items[0].Direction.IsKnown() {
+		t.Fatalf("expected Mutual not to be known")
+	}
+	if set := upd.DirectionOrBoth(); set != 0 || set.Has(*upd.Direction) {
+		t.Fatalf("expected an empty set for an unknown direction, got %d", set)
+	}
+
+	seek := upd.Items[1].Direction
+	if !seek.IsKnown() || !DirectionSetSeek.Has(seek) || DirectionSetOffer.Has(seek) {
+		t.Fatalf("expected %q to be classified as Seek", seek)
+	}
+	if !MatchingDirectionOffer.IsKnown() || MatchingDirection("").IsKnown() {
+		t.Fatalf("unexpected IsKnown results for the constants")
+	}
+}
+
+// TestMatchesSnapshotRequest_StreamOptions verifies that every filter of a
+// snapshot request carries over to the stream options.
+func TestMatchesSnapshotRequest_StreamOptions(t *testing.T) {
+	req := MatchesSnapshotRequest{
+		ProID:     "p_123",
+		Direction: MatchingDirectionSeek,
+		MatchesFilter: MatchesFilter{
+			MinScore:           0.75,
+			Limit:              LimitAll,
+			MinRationaleLength: 10,
+			MaxRationaleLength: 200,
+		},
+		Sort: MatchesSortScoreDesc,
+	}
+
+	want := MatchesStreamOptions{
+		Direction:          MatchingDirectionSeek,
+		MinScore:           0.75,
+		Limit:              LimitAll,
+		MinRationaleLength: 10,
+		MaxRationaleLength: 200,
+	}
+	if got := req.StreamOptions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+// TestSpeechUploadResponse_NormalizedWav verifies the accessors for a
+// present, null, missing and empty wav16kMonoPath.
+func TestSpeechUploadResponse_NormalizedWav(t *testing.T) {
+	for raw, want := range map[string]string{
+		`{"ok":true,"wav16kMonoPath":"/data/s_1/0.wav"}`: "/data/s_1/0.wav",
+		`{"ok":true,"wav16kMonoPath":null}`:              "",
+		`{"ok":true}`:                                    "",
+		`{"ok":true,"wav16kMonoPath":""}`:                "",
+	} {
+		var resp SpeechUploadResponse
+		if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+		path, ok := resp.NormalizedWavPath()
+		if path != want || ok != (want != "") || resp.HasNormalizedWav() != ok {
+			t.Fatalf("%s: got (%q, %v), HasNormalizedWav=%v", raw, path, ok, resp.HasNormalizedWav())
+		}
+	}
+
+	var nilResp *SpeechUploadResponse
+	if nilResp.HasNormalizedWav() {
+		t.Fatalf("expected a nil response to have no normalized WAV")
+	}
+}
@@ -0,0 +1,24 @@
+package manaxclient
+
+import (
+	"context"
+	"time"
+)
+
+// waitOrCancel blocks for d or until ctx is done, whichever comes first,
+// and returns ctx.Err() in the latter case. A non-positive d only checks
+// ctx. Every retry, reconnect and poll loop sleeps through it so that
+// cancellation is never delayed by a pending backoff.
+func waitOrCancel(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
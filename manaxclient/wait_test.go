@@ -0,0 +1,68 @@
+package manaxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWaitOrCancel verifies that the wait ends on the timer or, much
+// earlier, on cancellation.
+func TestWaitOrCancel(t *testing.T) {
+	if err := waitOrCancel(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("expected nil after the delay, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if err := waitOrCancel(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("wait ignored cancellation for %s", d)
+	}
+	if err := waitOrCancel(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled for zero delay, got %v", err)
+	}
+}
+
+// TestManagedMatchesStream_CancelDuringBackoff verifies that a reconnect
+// loop with a long backoff ends promptly once its context is cancelled.
+func TestManagedMatchesStream_CancelDuringBackoff(t *testing.T) {
+	var streams int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&streams, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+	}
+
+	client, server := newTestClient(t, handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewMemoryCursorStore()
+	_ = store.SaveCursor(ctx, matchesCursorKey("p_123", MatchingDirectionOffer), Cursor{UpdatedUTC: time.Now(), ID: 1})
+	policy := ReconnectPolicy{InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+
+	items, errs := client.ManagedMatchesStream(ctx, "p_123", MatchingDirectionOffer, MatchesFilter{}, store, policy)
+	for atomic.LoadInt32(&streams) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	start := time.Now()
+	for range items {
+	}
+	if err, ok := <-errs; ok {
+		t.Fatalf("expected cancellation to close the channels without error, got %v", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Fatalf("reconnect loop returned %s after cancellation", d)
+	}
+}
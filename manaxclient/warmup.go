@@ -0,0 +1,43 @@
+package manaxclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Warmup primes the connection pool ahead of a burst of requests (for
+// example a series of UploadSpeechAudio calls): it sends a HEAD request
+// to the base URL so that the TCP connection and TLS handshake are
+// already established when the first real request goes out.
+//
+// Warmup is best-effort. Any HTTP answer counts as success, whatever its
+// status, since only the connection matters; only transport errors
+// (DNS, connect, TLS, ctx) are returned. Whether later requests reuse the
+// connection depends on the transport: idle connections may be closed
+// by either side (see http.Transport.IdleConnTimeout), and HTTP/1.1
+// requests running concurrently still open additional connections.
+//
+// Like other non-streaming calls, Warmup is bounded by the maximum
+// request duration when ctx has no deadline (see WithMaxRequestDuration).
+func (c *Client) Warmup(ctx context.Context) error {
+	ctx = withOperation(ctx, "Warmup")
+	// Like the connectivity check, the request carries no credentials:
+	// the base URL is not an API endpoint.
+	u := c.normalizedBaseURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("Warmup: create request: %w", err)
+	}
+	req, cancel := c.boundRequest(req)
+	defer cancel()
+	resp, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("Warmup: %w", err)
+	}
+	// The body must be drained for the connection to return to the pool.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, DefaultMaxErrorBodyBytes))
+	resp.Body.Close()
+	return nil
+}
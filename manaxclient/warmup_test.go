@@ -0,0 +1,61 @@
+package manaxclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWarmup verifies that Warmup issues a HEAD request, succeeds even on
+// a non-2xx answer and leaves a connection that the next request reuses.
+func TestWarmup(t *testing.T) {
+	var heads, conns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&heads, 1)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup returned error: %v", err)
+	}
+	if n := atomic.LoadInt32(&heads); n != 1 {
+		t.Fatalf("expected one HEAD request, got %d", n)
+	}
+
+	_, err = client.UploadSpeechAudio(context.Background(), UploadSpeechAudioRequest{
+		ProID:     "p_123",
+		SessionID: "s_1",
+		Audio:     strings.NewReader("audio"),
+	})
+	if err != nil {
+		t.Fatalf("UploadSpeechAudio failed: %v", err)
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Fatalf("expected the upload to reuse the warm connection, got %d connections", n)
+	}
+
+	srv.Close()
+	if err := client.Warmup(context.Background()); err == nil {
+		t.Fatalf("expected an error once the server is gone")
+	}
+}